@@ -0,0 +1,148 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Aggregation is a calculation the API does over a whole result set, ex. Sum("Effort").
+// It is used with Client.Aggregate and Client.AggregateBy.
+type Aggregation struct {
+	function string
+	field    FieldRef
+}
+
+// Count counts the entities
+func Count() Aggregation {
+	return Aggregation{function: "count"}
+}
+
+// Sum adds up the values of field
+func Sum(field string) Aggregation {
+	return Aggregation{function: "sum", field: Field(field)}
+}
+
+// Avg averages the values of field
+func Avg(field string) Aggregation {
+	return Aggregation{function: "avg", field: Field(field)}
+}
+
+// Min finds the smallest value of field
+func Min(field string) Aggregation {
+	return Aggregation{function: "min", field: Field(field)}
+}
+
+// Max finds the largest value of field
+func Max(field string) Aggregation {
+	return Aggregation{function: "max", field: Field(field)}
+}
+
+// String renders the aggregation in the v2 query language
+func (a Aggregation) String() string {
+	if a.function == "count" {
+		return a.function
+	}
+	return fmt.Sprintf("%s(%s)", a.function, a.field)
+}
+
+func (a Aggregation) err() error {
+	if a.function == "" {
+		return fmt.Errorf("empty aggregation, use Count, Sum, Avg, Min or Max")
+	}
+	return a.field.err
+}
+
+// Aggregate will return the result of agg over every entity of entityType matching the filters,
+// computed by the API in a single request. An aggregation over no values, like the average of
+// an empty result set, is 0.
+func (c *Client) Aggregate(entityType string, agg Aggregation, filters ...QueryFilter) (float64, error) {
+	return c.AggregateCtx(c.context(), entityType, agg, filters...)
+}
+
+// AggregateCtx is Aggregate with a context that can cancel the request
+func (c *Client) AggregateCtx(ctx context.Context, entityType string, agg Aggregation, filters ...QueryFilter) (float64, error) {
+	if err := agg.err(); err != nil {
+		return 0, err
+	}
+	out := map[string]*float64{}
+	filters = append(append([]QueryFilter{}, filters...), Result(agg.function+":"+agg.String()))
+	if err := c.GetCtx(ctx, &out, entityType, nil, filters...); err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("error getting %s of %s", agg, entityType))
+	}
+	if out[agg.function] == nil {
+		return 0, nil
+	}
+	return *out[agg.function], nil
+}
+
+// AggregateBy will return the result of agg for each value of groupBy over every entity of entityType
+// matching the filters, ex. the sum of Effort per EntityState.Name, computed by the API in a single request.
+// Groups are keyed by the value of groupBy as text, and a group without a value is keyed by "".
+func (c *Client) AggregateBy(entityType, groupBy string, agg Aggregation, filters ...QueryFilter) (map[string]float64, error) {
+	return c.AggregateByCtx(c.context(), entityType, groupBy, agg, filters...)
+}
+
+// AggregateByCtx is AggregateBy with a context that can cancel the request
+func (c *Client) AggregateByCtx(ctx context.Context, entityType, groupBy string, agg Aggregation, filters ...QueryFilter) (map[string]float64, error) {
+	key := Field(groupBy)
+	if key.err != nil {
+		return nil, key.err
+	}
+	if err := agg.err(); err != nil {
+		return nil, err
+	}
+	out := struct {
+		Groups []map[string]json.RawMessage `json:"groups"`
+	}{}
+	result := fmt.Sprintf("groups:groupBy(%s).select({key,%s:%s})", key, agg.function, agg)
+	filters = append(append([]QueryFilter{}, filters...), Result(result))
+	if err := c.GetCtx(ctx, &out, entityType, nil, filters...); err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("error getting %s of %s by %s", agg, entityType, groupBy))
+	}
+	ret := make(map[string]float64, len(out.Groups))
+	for _, group := range out.Groups {
+		value := 0.0
+		if raw, ok := group[agg.function]; ok {
+			var v *float64
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("JSON decode failed on %s of %s by %s", agg, entityType, groupBy))
+			}
+			if v != nil {
+				value = *v
+			}
+		}
+		ret[groupKey(group["key"])] = value
+	}
+	return ret, nil
+}
+
+// groupKey returns the text of the key of a group, strings unquoted
+func groupKey(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	key := strings.TrimSpace(string(raw))
+	if key == "null" {
+		return ""
+	}
+	return key
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name       string
+		agg        Aggregation
+		wantResult string
+		response   string
+		want       float64
+		wantErr    bool
+	}{
+		{
+			name:       "count",
+			agg:        Count(),
+			wantResult: "{count:count}",
+			response:   `{"count": 12}`,
+			want:       12,
+		},
+		{
+			name:       "sum",
+			agg:        Sum("Effort"),
+			wantResult: "{sum:sum(Effort)}",
+			response:   `{"sum": 40.5}`,
+			want:       40.5,
+		},
+		{
+			name:       "average of nothing",
+			agg:        Avg("Effort"),
+			wantResult: "{avg:avg(Effort)}",
+			response:   `{"avg": null}`,
+			want:       0,
+		},
+		{
+			name:       "max",
+			agg:        Max("Feature.Effort"),
+			wantResult: "{max:max(Feature.Effort)}",
+			response:   `{"max": 8}`,
+			want:       8,
+		},
+		{
+			name:    "invalid field",
+			agg:     Min("Effort) or (Id"),
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v2/UserStories/", r.URL.Path)
+				assert.Equal(t, tt.wantResult, r.URL.Query().Get("result"))
+				assert.Equal(t, "Project.Id == 7", r.URL.Query().Get("where"))
+				_, _ = w.Write([]byte(tt.response))
+			})
+			mockClient, teardown := newMockClient(h, "example", "abcd1234")
+			defer teardown()
+
+			got, err := mockClient.Aggregate("UserStories", tt.agg, Where("Project.Id == 7"))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAggregateBy(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/UserStories/", r.URL.Path)
+		assert.Equal(t, "{groups:groupBy(EntityState.Name).select({key,sum:sum(Effort)})}", r.URL.Query().Get("result"))
+		_, _ = w.Write([]byte(`{"groups": [
+			{"key": "Open", "sum": 13},
+			{"key": "Done", "sum": 21.5},
+			{"key": null, "sum": null},
+			{"key": 3, "sum": 1}
+		]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	got, err := mockClient.AggregateBy("UserStories", "EntityState.Name", Sum("Effort"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"Open": 13, "Done": 21.5, "": 0, "3": 1}, got)
+
+	_, err = mockClient.AggregateBy("UserStories", "", Count())
+	assert.Error(t, err)
+}
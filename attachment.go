@@ -0,0 +1,179 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Attachment matches up with a targetprocess Attachment, a file attached to any general entity
+type Attachment struct {
+	client *Client
+
+	ID             int32     `json:"Id,omitempty"`
+	Name           string    `json:",omitempty"`
+	Description    string    `json:",omitempty"`
+	Date           *DateTime `json:",omitempty"`
+	MimeType       string    `json:",omitempty"`
+	Size           int64     `json:",omitempty"`
+	UniqueFileName string    `json:",omitempty"`
+	Owner          *User     `json:",omitempty"`
+	General        *General  `json:",omitempty"`
+}
+
+// AttachmentResponse is a representation of the http response for a group of Attachments
+type AttachmentResponse struct {
+	Items []Attachment
+	Next  string
+	Prev  string
+}
+
+// GetAttachments will return all attachments of the entity with the given ID
+func (c *Client) GetAttachments(entityID int32, filters ...QueryFilter) ([]Attachment, error) {
+	return c.GetAttachmentsCtx(c.context(), entityID, filters...)
+}
+
+// GetAttachmentsCtx is GetAttachments with a context that can cancel the requests
+func (c *Client) GetAttachmentsCtx(ctx context.Context, entityID int32, filters ...QueryFilter) ([]Attachment, error) {
+	var ret []Attachment
+	filters = append([]QueryFilter{WhereExpr(Field("General.Id").Eq(entityID))}, filters...)
+	it := c.NewIteratorCtx(ctx, "Attachments", filters...)
+	for it.Next() {
+		item := Attachment{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// UploadAttachment attaches the content of r to the entity with the given ID under the given file name,
+// and returns the created Attachment.
+//
+// The file is streamed to Targetprocess as it is read, so it is never held in memory as a whole. Since r
+// can only be read once, a failed upload is not retried.
+func (c *Client) UploadAttachment(entityID int32, fileName string, r io.Reader) (Attachment, error) {
+	return c.UploadAttachmentCtx(c.context(), entityID, fileName, r)
+}
+
+// UploadAttachmentCtx is UploadAttachment with a context that can cancel the request
+func (c *Client) UploadAttachmentCtx(ctx context.Context, entityID int32, fileName string, r io.Reader) (Attachment, error) {
+	u := c.siteURL.ResolveReference(&url.URL{Path: "UploadFile.ashx"})
+	body, contentType := multipartBody(entityID, fileName, r)
+	defer body.Close()
+
+	c.debugLog("[targetprocess] POST %s for entity %d: %s", u, entityID, fileName)
+	req, err := http.NewRequestWithContext(ctx, "POST", u.String(), body)
+	if err != nil {
+		return Attachment{}, errors.Wrapf(err, "Invalid POST request: %s", u)
+	}
+	req.Header.Add("Content-Type", contentType)
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := c.doRaw(req)
+	if err != nil {
+		return Attachment{}, errors.Wrap(err, fmt.Sprintf("error uploading %s to entity %d", fileName, entityID))
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	ret := Attachment{}
+	if err := json.NewDecoder(resp.Body).Decode(&ret); err != nil {
+		return Attachment{}, errors.Wrap(err, fmt.Sprintf("JSON decode failed on upload of %s", fileName))
+	}
+	c.debugLog(fmt.Sprintf("[targetprocess] Attachment uploaded. ID: %d", ret.ID))
+	ret.client = c
+	return ret, nil
+}
+
+// multipartBody streams the multipart form expected by UploadFile.ashx through a pipe, returning the
+// reader end along with its Content-Type
+func multipartBody(entityID int32, fileName string, r io.Reader) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		err := mw.WriteField("generalId", strconv.Itoa(int(entityID)))
+		if err == nil {
+			var part io.Writer
+			part, err = mw.CreateFormFile("attachment", fileName)
+			if err == nil {
+				_, err = io.Copy(part, r)
+			}
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, mw.FormDataContentType()
+}
+
+// DownloadAttachment writes the content of the Attachment with the given ID to w as it is received,
+// and returns the number of bytes written.
+//
+// The client's Timeout covers the whole download, so raise it with WithTimeout for large files.
+func (c *Client) DownloadAttachment(id int32, w io.Writer) (int64, error) {
+	return c.DownloadAttachmentCtx(c.context(), id, w)
+}
+
+// DownloadAttachmentCtx is DownloadAttachment with a context that can cancel the request
+func (c *Client) DownloadAttachmentCtx(ctx context.Context, id int32, w io.Writer) (int64, error) {
+	u := c.siteURL.ResolveReference(&url.URL{Path: "Attachment.aspx"})
+	u.RawQuery = url.Values{"AttachmentID": []string{strconv.Itoa(int(id))}}.Encode()
+
+	c.debugLog("[targetprocess] GET %s", u)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid GET request: %s", u)
+	}
+	resp, err := c.doRaw(req)
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("error downloading Attachment %d", id))
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, errors.Wrap(err, fmt.Sprintf("error downloading Attachment %d", id))
+	}
+	return n, nil
+}
+
+// Download writes the content of the Attachment to w. See Client.DownloadAttachment
+func (a Attachment) Download(w io.Writer) (int64, error) {
+	return a.DownloadCtx(a.client.context(), w)
+}
+
+// DownloadCtx is Download with a context that can cancel the request
+func (a Attachment) DownloadCtx(ctx context.Context, w io.Writer) (int64, error) {
+	if a.client == nil {
+		return 0, fmt.Errorf("Attachment %d has no client, get it from a Client method", a.ID)
+	}
+	return a.client.DownloadAttachmentCtx(ctx, a.ID, w)
+}
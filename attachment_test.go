@@ -0,0 +1,90 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadAttachment(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/UploadFile.ashx", r.URL.Path)
+		assert.Equal(t, "abcd1234", r.URL.Query().Get("access_token"))
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "42", r.FormValue("generalId"))
+		file, header, err := r.FormFile("attachment")
+		if !assert.NoError(t, err) {
+			return
+		}
+		content, _ := ioutil.ReadAll(file)
+		assert.Equal(t, "build.log", header.Filename)
+		assert.Equal(t, "all tests passed\n", string(content))
+		_, _ = w.Write([]byte(`{"Id": 7, "Name": "build.log", "Size": 17, "General": {"Id": 42}}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	attachment, err := mockClient.UploadAttachment(42, "build.log", strings.NewReader("all tests passed\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), attachment.ID)
+	assert.Equal(t, int64(17), attachment.Size)
+	assert.Equal(t, mockClient, attachment.client)
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	content := bytes.Repeat([]byte("screenshot"), 10000)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/Attachment.aspx", r.URL.Path)
+		if r.URL.Query().Get("AttachmentID") != "7" {
+			w.WriteHeader(404)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(content)
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	var buf bytes.Buffer
+	n, err := Attachment{client: mockClient, ID: 7}.Download(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), n)
+	assert.Equal(t, content, buf.Bytes())
+
+	_, err = mockClient.DownloadAttachment(8, &buf)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestGetAttachments(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Attachments/", r.URL.Path)
+		assert.Equal(t, "General.Id == 42", r.URL.Query().Get("where"))
+		_, _ = w.Write([]byte(`{"items": [{"id": 7, "name": "build.log", "mimeType": "text/plain"}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	attachments, err := mockClient.GetAttachments(42)
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 1)
+	assert.Equal(t, "text/plain", attachments[0].MimeType)
+}
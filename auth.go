@@ -0,0 +1,159 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	redacted = "REDACTED"
+)
+
+// Authenticator adds credentials to every request made by the Client.
+// see here: https://dev.targetprocess.com/docs/authentication
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// HeaderTokenAuth authenticates with an access token sent in the Authorization header,
+// keeping it out of URLs and therefore out of proxy and server access logs
+type HeaderTokenAuth struct {
+	Token string
+}
+
+// Authenticate implements Authenticator
+func (a HeaderTokenAuth) Authenticate(req *http.Request) error {
+	if a.Token == "" {
+		return errors.New("access token cannot be empty")
+	}
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// BasicAuth authenticates with a Targetprocess login and password
+type BasicAuth struct {
+	Login    string
+	Password string
+}
+
+// Authenticate implements Authenticator
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	if a.Login == "" {
+		return errors.New("login cannot be empty")
+	}
+	req.SetBasicAuth(a.Login, a.Password)
+	return nil
+}
+
+// QueryTokenAuth authenticates with an access token sent as the access_token query parameter.
+// This is what NewClient uses. Prefer HeaderTokenAuth where possible, as URLs tend to end up in logs.
+type QueryTokenAuth struct {
+	Token string
+}
+
+// Authenticate implements Authenticator
+func (a QueryTokenAuth) Authenticate(req *http.Request) error {
+	if a.Token == "" {
+		return errors.New("access token cannot be empty")
+	}
+	values := req.URL.Query()
+	values.Set("access_token", a.Token)
+	req.URL.RawQuery = values.Encode()
+	return nil
+}
+
+// authenticator returns the Authenticator set on the client, falling back to
+// sending Token in the query string for clients that only set a Token
+func (c *Client) authenticator() Authenticator {
+	if c.Authenticator != nil {
+		return c.Authenticator
+	}
+	if c.Token != "" {
+		return QueryTokenAuth{Token: c.Token}
+	}
+	return nil
+}
+
+// redactSecrets replaces any credential of the client found in s. Response bodies can contain
+// the access token, e.g. in Next urls, so URL based redaction alone isn't enough for logs.
+func (c *Client) redactSecrets(s string) string {
+	var secrets []string
+	switch auth := c.authenticator().(type) {
+	case QueryTokenAuth:
+		secrets = append(secrets, auth.Token)
+	case *QueryTokenAuth:
+		secrets = append(secrets, auth.Token)
+	case HeaderTokenAuth:
+		secrets = append(secrets, auth.Token)
+	case *HeaderTokenAuth:
+		secrets = append(secrets, auth.Token)
+	case BasicAuth:
+		secrets = append(secrets, auth.Password)
+	case *BasicAuth:
+		secrets = append(secrets, auth.Password)
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.Replace(s, secret, redacted, -1)
+			// tokens usually end in '=', which is escaped when they are echoed back in a URL
+			s = strings.Replace(s, url.QueryEscape(secret), redacted, -1)
+		}
+	}
+	return s
+}
+
+// redactError removes credentials from the URL the net/http package includes in its errors
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			urlErr.URL = redactURL(u)
+		} else {
+			urlErr.URL = redacted
+		}
+	}
+	return err
+}
+
+// redactURL returns the URL as a string with the access token and any user info redacted
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	clean := *u
+	if clean.User != nil {
+		clean.User = url.User(redacted)
+	}
+	clean.RawQuery = redactQuery(clean.Query())
+	return clean.String()
+}
+
+// redactQuery encodes the query values with the access token redacted
+func redactQuery(values url.Values) string {
+	if values.Get("access_token") == "" {
+		return values.Encode()
+	}
+	clean := url.Values{}
+	for key, value := range values {
+		clean[key] = value
+	}
+	clean.Set("access_token", redacted)
+	return clean.Encode()
+}
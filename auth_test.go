@@ -0,0 +1,126 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestAuthenticators(t *testing.T) {
+	tests := []struct {
+		name          string
+		auth          Authenticator
+		wantQuery     string
+		wantAuthHdr   string
+		wantBasicUser string
+		wantBasicPass string
+	}{
+		{
+			name:        "header token",
+			auth:        HeaderTokenAuth{Token: "superSecretToken"},
+			wantAuthHdr: "Bearer superSecretToken",
+		},
+		{
+			name:          "basic auth",
+			auth:          BasicAuth{Login: "jane", Password: "hunter2"},
+			wantBasicUser: "jane",
+			wantBasicPass: "hunter2",
+		},
+		{
+			name:      "query token",
+			auth:      QueryTokenAuth{Token: "superSecretToken"},
+			wantQuery: "superSecretToken",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.wantQuery, r.URL.Query().Get("access_token"))
+				if tt.wantBasicUser != "" {
+					user, pass, ok := r.BasicAuth()
+					assert.True(t, ok)
+					assert.Equal(t, tt.wantBasicUser, user)
+					assert.Equal(t, tt.wantBasicPass, pass)
+				} else {
+					assert.Equal(t, tt.wantAuthHdr, r.Header.Get("Authorization"))
+				}
+				_, _ = w.Write([]byte(okResponse))
+			})
+			mockClient, teardown := newMockClient(h, "example", "")
+			defer teardown()
+			mockClient.Authenticator = tt.auth
+
+			assert.NoError(t, mockClient.Get(new(genericResponse), "Users", nil))
+			assert.NoError(t, mockClient.Post(new(genericResponse), "UserStory", nil, nil))
+		})
+	}
+}
+
+func TestAuthenticatorMissingCredentials(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent without credentials")
+	})
+	mockClient, teardown := newMockClient(h, "example", "")
+	defer teardown()
+	mockClient.Authenticator = HeaderTokenAuth{}
+
+	assert.Error(t, mockClient.Get(new(genericResponse), "Users", nil))
+}
+
+func TestCredentialsRedacted(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("skip") == "" {
+			_, _ = w.Write([]byte(`{"Items": [], "Next": "https://example.tpondemand.com/api/v2/Users?access_token=superSecretToken&skip=25"}`))
+			return
+		}
+		w.WriteHeader(500)
+	})
+	mockClient, teardown := newMockClient(h, "example", "superSecretToken")
+	logger := &recordingLogger{}
+	mockClient.Logger = logger
+	mockClient.RetryPolicy = testRetryPolicy()
+
+	_, err := mockClient.GetUsers()
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "superSecretToken")
+
+	// network errors include the full url
+	teardown()
+	_, err = mockClient.GetUsers()
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "superSecretToken")
+
+	assert.NotEmpty(t, logger.lines)
+	for _, line := range logger.lines {
+		assert.False(t, strings.Contains(line, "superSecretToken"), "credentials logged: %s", line)
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Bug matches up with a targetprocess Bug
+type Bug struct {
+	client *Client
+
+	ID                  int32           `json:"Id,omitempty"`
+	Name                string          `json:",omitempty"`
+	Description         string          `json:",omitempty"`
+	StartDate           *DateTime       `json:",omitempty"`
+	EndDate             *DateTime       `json:",omitempty"`
+	CreateDate          *DateTime       `json:",omitempty"`
+	ModifyDate          *DateTime       `json:",omitempty"`
+	NumericPriority     float64         `json:",omitempty"`
+	CustomFields        []CustomField   `json:",omitempty"`
+	Effort              float32         `json:",omitempty"`
+	EffortCompleted     float32         `json:",omitempty"`
+	EffortToDo          float32         `json:",omitempty"`
+	Project             *Project        `json:",omitempty"`
+	Progress            float32         `json:",omitempty"`
+	TimeSpent           float32         `json:",omitempty"`
+	TimeRemain          float32         `json:",omitempty"`
+	LastStateChangeDate *DateTime       `json:",omitempty"`
+	Assignments         *Assignments    `json:",omitempty"`
+	ResponsibleTeam     *TeamAssignment `json:",omitempty"`
+	Team                *Team           `json:",omitempty"`
+	Priority            *Priority       `json:",omitempty"`
+	EntityState         *EntityState    `json:",omitempty"`
+	AssignedUser        *AssignedUser   `json:",omitempty"`
+	Severity            *Severity       `json:",omitempty"`
+	Build               *Build          `json:",omitempty"`
+	UserStory           *UserStory      `json:",omitempty"`
+	Feature             *Feature        `json:",omitempty"`
+	Release             *Release        `json:",omitempty"`
+	Iteration           *Iteration      `json:",omitempty"`
+	TeamIteration       *TeamIteration  `json:",omitempty"`
+}
+
+// BugResponse is a representation of the http response for a group of Bugs
+type BugResponse struct {
+	Items []Bug
+	Next  string
+	Prev  string
+}
+
+// Severity defines how severe a Bug is, ex. Blocking or Small
+type Severity struct {
+	ID         int32  `json:"Id,omitempty"`
+	Name       string `json:",omitempty"`
+	Importance int32  `json:",omitempty"`
+}
+
+// SeverityResponse is a representation of the http response for a group of Severities
+type SeverityResponse struct {
+	Items []Severity
+	Next  string
+	Prev  string
+}
+
+// Build is a build of a project that bugs can be found in
+type Build struct {
+	ID        int32     `json:"Id,omitempty"`
+	Name      string    `json:",omitempty"`
+	BuildDate *DateTime `json:",omitempty"`
+	Project   *Project  `json:",omitempty"`
+}
+
+// NewBug creates a new Bug with the required fields of
+// name, description, and project.
+func NewBug(c *Client, name, description, project string) (Bug, error) {
+	return NewBugCtx(c.context(), c, name, description, project)
+}
+
+// NewBugCtx is NewBug with a context that can cancel the requests
+func NewBugCtx(ctx context.Context, c *Client, name, description, project string) (Bug, error) {
+	b := Bug{
+		client:      c,
+		Name:        name,
+		Description: description,
+	}
+	err := b.SetProjectCtx(ctx, project)
+	if err != nil {
+		return Bug{}, err
+	}
+	return b, nil
+}
+
+// NewBug will make a Bug assigned to the Project that this method is built off of and for the given Team
+func (p Project) NewBug(name, description, team string) (Bug, error) {
+	return p.NewBugCtx(p.client.context(), name, description, team)
+}
+
+// NewBugCtx is NewBug with a context that can cancel the requests
+func (p Project) NewBugCtx(ctx context.Context, name, description, team string) (Bug, error) {
+	b := Bug{
+		client:      p.client,
+		Name:        name,
+		Description: description,
+	}
+	p.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Team: %s", team))
+	t, err := p.client.GetTeamCtx(ctx, team)
+	if err != nil {
+		return Bug{}, err
+	}
+	b.Project = &p
+	b.Team = &t
+	return b, nil
+}
+
+// NewBug will make a Bug assigned to the Team that this method is built off of
+func (t Team) NewBug(name, description, project string) (Bug, error) {
+	return t.NewBugCtx(t.client.context(), name, description, project)
+}
+
+// NewBugCtx is NewBug with a context that can cancel the requests
+func (t Team) NewBugCtx(ctx context.Context, name, description, project string) (Bug, error) {
+	b, err := NewBugCtx(ctx, t.client, name, description, project)
+	if err != nil {
+		return Bug{}, err
+	}
+	b.Team = &t
+	return b, nil
+}
+
+// NewBug will make a Bug linked to the UserStory that this method is built off of,
+// in the same Project and for the same Team
+func (us UserStory) NewBug(name, description string) (Bug, error) {
+	if us.ID == 0 {
+		return Bug{}, fmt.Errorf("UserStory %s has not been created yet", us.Name)
+	}
+	return Bug{
+		client:      us.client,
+		Name:        name,
+		Description: description,
+		Project:     us.Project,
+		Team:        us.Team,
+		UserStory:   &UserStory{ID: us.ID},
+	}, nil
+}
+
+// SetProject sets the Project field for a bug
+func (b *Bug) SetProject(project string) error {
+	return b.SetProjectCtx(b.client.context(), project)
+}
+
+// SetProjectCtx is SetProject with a context that can cancel the requests
+func (b *Bug) SetProjectCtx(ctx context.Context, project string) error {
+	b.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Project: %s", project))
+	p, err := b.client.GetProjectCtx(ctx, project)
+	if err != nil {
+		return err
+	}
+	b.Project = &p
+	return nil
+}
+
+// SetTeam sets the Team field for a bug
+func (b *Bug) SetTeam(team string) error {
+	return b.SetTeamCtx(b.client.context(), team)
+}
+
+// SetTeamCtx is SetTeam with a context that can cancel the requests
+func (b *Bug) SetTeamCtx(ctx context.Context, team string) error {
+	b.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Team: %s", team))
+	t, err := b.client.GetTeamCtx(ctx, team)
+	if err != nil {
+		return err
+	}
+	b.Team = &t
+	return nil
+}
+
+// SetSeverity sets the Severity field for a bug
+func (b *Bug) SetSeverity(severity string) error {
+	return b.SetSeverityCtx(b.client.context(), severity)
+}
+
+// SetSeverityCtx is SetSeverity with a context that can cancel the requests
+func (b *Bug) SetSeverityCtx(ctx context.Context, severity string) error {
+	s, err := b.client.GetSeverityCtx(ctx, severity)
+	if err != nil {
+		return err
+	}
+	b.Severity = &s
+	return nil
+}
+
+// SetAssignedUserID assigns the Bug to a User based on their ID number
+func (b *Bug) SetAssignedUserID(userID int32) {
+	u := User{
+		ID: userID,
+	}
+	au := Assignment{
+		GeneralUser: &u,
+	}
+	assignments := Assignments{Items: []Assignment{au}}
+	b.Assignments = &assignments
+}
+
+// GetBugByID will return the Bug with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetBugByID(id int32) (Bug, error) {
+	return c.GetBugByIDCtx(c.context(), id)
+}
+
+// GetBugByIDCtx is GetBugByID with a context that can cancel the request
+func (c *Client) GetBugByIDCtx(ctx context.Context, id int32) (Bug, error) {
+	ret := Bug{}
+	err := c.GetByIDCtx(ctx, &ret, "Bugs", id)
+	if err != nil {
+		return Bug{}, errors.Wrap(err, fmt.Sprintf("error getting Bug with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetBugs will return all bugs
+//
+// Use with caution if you have a lot and are not setting the MaxPerPage to a high number
+// as it could cause a lot of requests to the API and may take a long time.
+// If you know you have a lot you may want to include the QueryFilter MaxPerPage
+func (c *Client) GetBugs(page bool, filters ...QueryFilter) ([]Bug, error) {
+	return c.GetBugsCtx(c.context(), page, filters...)
+}
+
+// GetBugsCtx is GetBugs with a context that can cancel the requests
+func (c *Client) GetBugsCtx(ctx context.Context, page bool, filters ...QueryFilter) ([]Bug, error) {
+	var ret []Bug
+	it := c.NewIteratorCtx(ctx, "Bugs", filters...)
+	if !page {
+		it.MaxPages(1)
+	}
+	for it.Next() {
+		item := Bug{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetSeverity will return a single Severity based on its name
+func (c *Client) GetSeverity(name string) (Severity, error) {
+	return c.GetSeverityCtx(c.context(), name)
+}
+
+// GetSeverityCtx is GetSeverity with a context that can cancel the requests
+func (c *Client) GetSeverityCtx(ctx context.Context, name string) (Severity, error) {
+	c.debugLog(fmt.Sprintf("[targetprocess] attempting to get Severity: %s", name))
+	out := SeverityResponse{}
+	err := c.GetCtx(ctx, &out, "Severity", nil,
+		WhereExpr(Field("Name").Eq(name)),
+		First(),
+	)
+	if err != nil {
+		return Severity{}, errors.Wrap(err, fmt.Sprintf("error getting Severity with name '%s'", name))
+	}
+	if len(out.Items) < 1 {
+		return Severity{}, fmt.Errorf("no Severity found with the name: %s", name)
+	}
+	return out.Items[0], nil
+}
+
+// Create takes a Bug struct and crafts a POST to make it so in TP
+// it returns the ID of the Bug created as well as a link to the entity
+// on the Target Process frontend
+func (b Bug) Create() (int32, string, error) {
+	return b.CreateCtx(b.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the requests
+func (b Bug) CreateCtx(ctx context.Context) (int32, string, error) {
+	client := b.client
+	resp := &struct {
+		ID int32 `json:"Id"`
+	}{}
+	body, err := json.Marshal(b)
+	if err != nil {
+		return 0, "", errors.Wrap(err, fmt.Sprintf("error marshaling POST body for Bug %s", b.Name))
+	}
+
+	client.debugLog(fmt.Sprintf("Attempting to POST Bug: %+v", b))
+	err = client.PostCtx(ctx, resp, "Bug", nil, body)
+	if err != nil {
+		return 0, "", errors.Wrap(err, fmt.Sprintf("error POSTing Bug %s", b.Name))
+	}
+	client.debugLog("[targetprocess] Successfully POSTed Bug")
+	client.debugLog(fmt.Sprintf("[targetprocess] Bug created. ID: %d", resp.ID))
+	link := client.EntityURL(resp.ID)
+	return resp.ID, link, nil
+}
+
+// Update sends only the given fields of the Bug to Targetprocess, leaving every other field untouched,
+// and returns the Bug as it is after the update.
+// Fields are named as in the JSON, ex. b.Update("Severity", "Build")
+func (b Bug) Update(fields ...string) (Bug, error) {
+	return b.UpdateCtx(b.client.context(), fields...)
+}
+
+// UpdateCtx is Update with a context that can cancel the request
+func (b Bug) UpdateCtx(ctx context.Context, fields ...string) (Bug, error) {
+	client := b.client
+	if client == nil {
+		return Bug{}, fmt.Errorf("Bug %d has no client, get it from a Client method", b.ID)
+	}
+	body, err := updateBody(b, b.ID, fields)
+	if err != nil {
+		return Bug{}, errors.Wrap(err, fmt.Sprintf("error building update for Bug %d", b.ID))
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Attempting to update Bug %d: %s", b.ID, body))
+	ret := Bug{}
+	err = client.UpdateCtx(ctx, &ret, "Bugs", b.ID, body)
+	if err != nil {
+		return Bug{}, errors.Wrap(err, fmt.Sprintf("error updating Bug %d", b.ID))
+	}
+	ret.client = client
+	return ret, nil
+}
+
+// Delete will delete the Bug in Targetprocess
+func (b Bug) Delete() error {
+	return b.DeleteCtx(b.client.context())
+}
+
+// DeleteCtx is Delete with a context that can cancel the request
+func (b Bug) DeleteCtx(ctx context.Context) error {
+	if b.client == nil {
+		return fmt.Errorf("Bug %d has no client, get it from a Client method", b.ID)
+	}
+	return b.client.DeleteCtx(ctx, "Bugs", b.ID)
+}
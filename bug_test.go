@@ -0,0 +1,130 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleUserStory_NewBug() {
+	tpClient, err := NewClient("exampleaccount", "superSecretToken")
+	if err != nil {
+		fmt.Println("Failed to create tp client:", err)
+		os.Exit(1)
+	}
+	us, err := tpClient.GetUserStoryByID(1234)
+	if err != nil {
+		fmt.Println("ERROR:", err)
+		os.Exit(1)
+	}
+	bug, err := us.NewBug("Login fails", "Steps to reproduce...")
+	if err != nil {
+		fmt.Println("ERROR:", err)
+		os.Exit(1)
+	}
+	if err := bug.SetSeverity("Blocking"); err != nil {
+		fmt.Println("ERROR:", err)
+	}
+	_, link, err := bug.Create()
+	if err != nil {
+		fmt.Println("ERROR:", err)
+	}
+	fmt.Printf("Bug created here: %s\n", link)
+}
+
+func TestBugCreate(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/Severity/":
+			assert.Equal(t, "Name == 'Blocking'", r.URL.Query().Get("where"))
+			_, _ = w.Write([]byte(`{"items": [{"id": 1, "name": "Blocking", "importance": 1}]}`))
+		case "/api/v1/Bug/":
+			assert.Equal(t, "POST", r.Method)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{
+				"Name": "Login fails",
+				"Description": "Steps to reproduce",
+				"Project": {"Id": 7},
+				"Team": {"Id": 3},
+				"UserStory": {"Id": 42},
+				"Severity": {"Id": 1, "Name": "Blocking", "Importance": 1}
+			}`, string(body))
+			_, _ = w.Write([]byte(`{"Id": 100}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	us := UserStory{client: mockClient, ID: 42, Project: &Project{ID: 7}, Team: &Team{ID: 3}}
+	bug, err := us.NewBug("Login fails", "Steps to reproduce")
+	assert.NoError(t, err)
+	assert.NoError(t, bug.SetSeverity("Blocking"))
+
+	id, link, err := bug.Create()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(100), id)
+	assert.Equal(t, "https://example.tpondemand.com/entity/100/RestUI/board.aspx", link)
+
+	_, err = UserStory{client: mockClient}.NewBug("Not created", "")
+	assert.Error(t, err)
+}
+
+func TestGetBugs(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Bugs/", r.URL.Path)
+		if r.URL.Query().Get("skip") == "" {
+			_, _ = w.Write([]byte(`{"items": [{"id": 1, "severity": {"id": 2, "name": "Normal"}}], "next": "https://example.tpondemand.com/api/v2/Bugs?skip=1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items": [{"id": 2}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	bugs, err := mockClient.GetBugs(true)
+	assert.NoError(t, err)
+	assert.Len(t, bugs, 2)
+	assert.Equal(t, "Normal", bugs[0].Severity.Name)
+	assert.Equal(t, mockClient, bugs[1].client)
+}
+
+func TestNewBugCtxCancelled(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent with a cancelled context")
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewBugCtx(ctx, mockClient, "Bug", "", "Project")
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = Project{client: mockClient}.NewBugCtx(ctx, "Bug", "", "Team")
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	b := Bug{client: mockClient}
+	assert.True(t, errors.Is(b.SetSeverityCtx(ctx, "Blocking"), context.Canceled))
+	assert.True(t, errors.Is(b.SetPriorityCtx(ctx, "High"), context.Canceled))
+}
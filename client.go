@@ -33,41 +33,60 @@ const (
 	userAgent = "go-targetprocess"
 )
 
-var (
-	defaultClient *http.Client
+const (
+	defaultTimeout = 15 * time.Second
 )
 
-func init() {
-	defaultClient = http.DefaultClient
-}
-
-// Client is the API client for Targetprocess. Create this using NewClient.
+// Client is the API client for Targetprocess. Create this using NewClient or NewClientWithOptions.
 // This can also be constructed manually but it isn't recommended.
 type Client struct {
 	// account is a place to hold the account name for this instance
 	account string
 
+	// siteURL is the root of the Targetprocess instance, used for links to entities
+	siteURL *url.URL
+
+	// pathPrefix is an optional path the Targetprocess instance is served under
+	pathPrefix string
+
 	// baseURL is the base URL for v1 API requests.
 	baseURL *url.URL
 
 	// baseURLReadOnly is the base URL for v2 API requests.
 	baseURLReadOnly *url.URL
 
-	// Client is the HTTP client to use for communication.
+	// Client is the HTTP client to use for communication. Every Client created by
+	// NewClient or NewClientWithOptions has its own unless one is passed in WithHTTPClient.
 	Client *http.Client
 
+	// transport is an optional RoundTripper to use instead of the HTTP client's transport
+	transport http.RoundTripper
+
 	// Logger is an optional logging interface for debugging
 	Logger logger
 
-	// Token is the user access token to authenticate to the Targetprocess instance
+	// Authenticator adds credentials to every request. When it is nil, Token is sent
+	// in the query string instead.
+	Authenticator Authenticator
+
+	// Token is the user access token to authenticate to the Targetprocess instance.
+	// It is only used when Authenticator is nil.
 	Token string
 
-	// Timeout is the timeout used in any request made
+	// Timeout is the timeout used for each attempt of any request made, including reading
+	// the response body. Zero means no timeout beyond the one set on the HTTP client.
 	Timeout time.Duration
 
 	// UserAgent is the user agent to send with API requests
 	UserAgent string
 
+	// RetryPolicy controls how failed requests are retried. A nil RetryPolicy disables retries.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter is an optional limiter that every request waits on before being sent.
+	// The same RateLimiter can be shared by clients that should share a quota.
+	RateLimiter *RateLimiter
+
 	ctx context.Context
 }
 
@@ -85,66 +104,118 @@ type logger interface {
 // token is your user access token taken from your account settings
 // see here: https://dev.targetprocess.com/docs/authentication#token-authentication
 func NewClient(account, token string) (*Client, error) {
-	c := defaultClient
-	c.Timeout = 15 * time.Second
-	baseURLString := fmt.Sprintf("https://%s.tpondemand.com/api/v1/", account)
-	baseURLReadOnlyString := fmt.Sprintf("https://%s.tpondemand.com/api/v2/", account)
-	baseURL, err := url.Parse(baseURLString)
-	if err != nil {
-		return nil, err
+	return NewClientWithOptions(WithAccount(account), WithToken(token))
+}
+
+// NewClientWithOptions will create a new Targetprocess client configured by the given options.
+// Either WithAccount or WithBaseURL is required.
+//
+// Example for a self-hosted instance:
+//   client, err := NewClientWithOptions(
+//     WithBaseURL("https://tp.example.com"),
+//     WithPathPrefix("/targetprocess"),
+//     WithToken("superSecretToken"),
+//   )
+func NewClientWithOptions(opts ...ClientOption) (*Client, error) {
+	client := &Client{
+		Client:      &http.Client{},
+		Timeout:     defaultTimeout,
+		UserAgent:   userAgent,
+		RetryPolicy: DefaultRetryPolicy(),
+		ctx:         context.Background(),
 	}
-	baseURLReadOnly, err := url.Parse(baseURLReadOnlyString)
-	if err != nil {
+	for _, opt := range opts {
+		if err := opt(client); err != nil {
+			return nil, err
+		}
+	}
+	if err := client.resolveURLs(); err != nil {
 		return nil, err
 	}
-	return &Client{
-		account:         account,
-		baseURL:         baseURL,
-		baseURLReadOnly: baseURLReadOnly,
-		Client:          c,
-		Token:           token,
-		UserAgent:       userAgent,
-		ctx:             context.Background(),
-	}, nil
+	if client.transport != nil {
+		// Copy the HTTP client so one passed in by the caller isn't modified
+		httpClient := *client.Client
+		httpClient.Transport = client.transport
+		client.Client = &httpClient
+	}
+	return client, nil
 }
 
-// WithContext takes a context.Context, sets it as context on the client and returns
-// a Client pointer.
+// WithContext takes a context.Context and sets it as context on the client. It is used by
+// every method that doesn't take a context itself, the *Ctx variants use the one passed in instead.
 func (c *Client) WithContext(ctx context.Context) {
 	c.ctx = ctx
 }
 
+// context returns the context set by WithContext
+func (c *Client) context() context.Context {
+	if c == nil || c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// EntityURL returns a link to the entity with the given ID that should work in a browser
+func (c *Client) EntityURL(entityID int32) string {
+	return fmt.Sprintf("%sentity/%d/RestUI/board.aspx", c.siteURL, entityID)
+}
+
 // Get is a generic HTTP GET call to the targetprocess api passing in the type of entity and any query filters
 func (c *Client) Get(out interface{}, entityType string, values url.Values, filters ...QueryFilter) error {
-	rel, err := url.Parse(entityType + "/")
+	return c.GetCtx(c.context(), out, entityType, values, filters...)
+}
+
+// GetCtx is Get with a context that can cancel the request
+func (c *Client) GetCtx(ctx context.Context, out interface{}, entityType string, values url.Values, filters ...QueryFilter) error {
+	return c.get(ctx, out, c.baseURLReadOnly, entityType+"/", values, filters...)
+}
+
+// GetByID is a generic HTTP GET call to the v1 api for a single entity, passing in the type
+// of entity (ex. UserStories), its ID and any query filters. The error returned when there is
+// no entity with that ID satisfies IsNotFound.
+func (c *Client) GetByID(out interface{}, entityType string, id int32, filters ...QueryFilter) error {
+	return c.GetByIDCtx(c.context(), out, entityType, id, filters...)
+}
+
+// GetByIDCtx is GetByID with a context that can cancel the request
+func (c *Client) GetByIDCtx(ctx context.Context, out interface{}, entityType string, id int32, filters ...QueryFilter) error {
+	return c.get(ctx, out, c.baseURL, fmt.Sprintf("%s/%d", entityType, id), nil, filters...)
+}
+
+// get makes a GET request to path relative to base, which is either the v1 or v2 base URL
+func (c *Client) get(ctx context.Context, out interface{}, base *url.URL, path string, values url.Values, filters ...QueryFilter) error {
+	rel, err := url.Parse(path)
 	if err != nil {
-		return errors.Wrapf(err, "Error parsing entity type: %s", entityType)
+		return errors.Wrapf(err, "Error parsing entity type: %s", path)
 	}
-	u := c.baseURLReadOnly.ResolveReference(rel)
+	u := base.ResolveReference(rel)
 
-	if values == nil {
-		values = url.Values{}
+	version := APIv2
+	if base == c.baseURL {
+		version = APIv1
 	}
-
-	for _, filter := range filters {
-		values, err = filter(values)
-		if err != nil {
-			return errors.Wrap(err, "Error running query filter")
-		}
+	values, err = applyFilters(version, values, filters)
+	if err != nil {
+		return errors.Wrap(err, "Error running query filter")
 	}
 	values = c.defaultParams(values)
 
-	c.debugLog("[targetprocess] GET %s%s?%s", c.baseURLReadOnly, entityType, values.Encode())
+	c.debugLog("[targetprocess] GET %s%s?%s", base, path, redactQuery(values))
 	fullURL := fmt.Sprintf("%s?%s", u.String(), values.Encode())
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
-		return errors.Wrapf(err, "Invalid GET request: %s/%s", c.baseURLReadOnly, entityType)
+		return errors.Wrapf(err, "Invalid GET request: %s%s", base, path)
 	}
-	return c.do(out, req, entityType)
+	return c.do(out, req, strings.TrimSuffix(path, "/"))
 }
 
 // GetNext is a helper method to get the next page of results from a query.
 func (c *Client) GetNext(out interface{}, nextURL string) error {
+	return c.GetNextCtx(c.context(), out, nextURL)
+}
+
+// GetNextCtx is GetNext with a context that can cancel the request
+func (c *Client) GetNextCtx(ctx context.Context, out interface{}, nextURL string) error {
 	prevFull, err := url.Parse(nextURL)
 	if err != nil {
 		return errors.Wrapf(err, "Invalid Next URL: %s", nextURL)
@@ -162,14 +233,27 @@ func (c *Client) GetNext(out interface{}, nextURL string) error {
 		return errors.Wrapf(err, "Invalid Next URL Entity Type: %s", entityURLType)
 	}
 
-	return c.Get(out, entityType, prevFull.Query())
+	// The Next url includes the access token when it was sent in the query, leave it to the Authenticator instead
+	values := prevFull.Query()
+	values.Del("access_token")
+	return c.GetCtx(ctx, out, entityType, values)
 }
 
 // Post is for both creating and updating objects in TargetProcess
 func (c *Client) Post(out interface{}, entityType string, values url.Values, body []byte) error {
-	rel, err := url.Parse(entityType + "/")
+	return c.PostCtx(c.context(), out, entityType, values, body)
+}
+
+// PostCtx is Post with a context that can cancel the request
+func (c *Client) PostCtx(ctx context.Context, out interface{}, entityType string, values url.Values, body []byte) error {
+	return c.write(ctx, "POST", out, entityType+"/", values, body)
+}
+
+// write makes a request with a body to path relative to the v1 base URL
+func (c *Client) write(ctx context.Context, method string, out interface{}, path string, values url.Values, body []byte) error {
+	rel, err := url.Parse(path)
 	if err != nil {
-		return errors.Wrapf(err, "Error parsing entity type: %s", entityType)
+		return errors.Wrapf(err, "Error parsing entity type: %s", path)
 	}
 	u := c.baseURL.ResolveReference(rel)
 
@@ -178,28 +262,23 @@ func (c *Client) Post(out interface{}, entityType string, values url.Values, bod
 	}
 	values = c.defaultParams(values)
 
-	c.debugLog("[targetprocess] POST %s/%s?%s", c.baseURL, entityType, values.Encode())
+	c.debugLog("[targetprocess] %s %s%s?%s", method, c.baseURL, path, redactQuery(values))
 	fullURL := fmt.Sprintf("%s?%s", u.String(), values.Encode())
 
-	req, err := http.NewRequest("POST", fullURL, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewBuffer(body))
 	if err != nil {
-		return errors.Wrapf(err, "Invalid POST request: %s/%s", c.baseURL, entityType)
+		return errors.Wrapf(err, "Invalid %s request: %s%s", method, c.baseURL, path)
 	}
-	return c.do(out, req, entityType)
+	return c.do(out, req, strings.TrimSuffix(path, "/"))
 }
 
+// do sends the request and decodes the JSON response into out. The response is discarded when out is nil.
 func (c *Client) do(out interface{}, req *http.Request, urlPath string) error {
-	noParameterURL := fmt.Sprintf("%s://%s%s", req.URL.Scheme, req.URL.Host, req.URL.Path)
-
-	// Set the headers that will be required for every request
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "application/json")
-	if c.UserAgent != "" {
-		req.Header.Add("User-Agent", c.UserAgent)
-	}
-	resp, err := c.Client.Do(req)
+	resp, err := c.doRaw(req)
 	if err != nil {
-		return errors.Wrapf(err, "HTTP request failure on %s", noParameterURL)
+		return err
 	}
 
 	// Empty the body and close it to reuse the Transport
@@ -208,26 +287,116 @@ func (c *Client) do(out interface{}, req *http.Request, urlPath string) error {
 		_ = resp.Body.Close()
 	}()
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return makeHTTPClientError(urlPath, resp)
-	}
-
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return errors.Wrapf(err, "HTTP Read error on response for %s", urlPath)
 	}
 	c.debugLog(fmt.Sprintf("[targetprocess] raw response: %s", string(b)))
+	if out == nil {
+		return nil
+	}
 	err = json.Unmarshal(b, out)
 	if err != nil {
-		return errors.Wrapf(err, "JSON decode failed on %s:\n%s", urlPath, string(b))
+		return errors.Wrapf(err, "JSON decode failed on %s:\n%s", urlPath, c.redactSecrets(string(b)))
 	}
 	return nil
 }
 
-func (c *Client) defaultParams(v url.Values) url.Values {
-	if c.Token != "" {
-		v.Add("access_token", c.Token)
+// doRaw authenticates and sends the request as is, leaving the content headers to the caller. The response
+// is only returned when it has a 2xx status, and the caller must close its body.
+func (c *Client) doRaw(req *http.Request) (*http.Response, error) {
+	noParameterURL := fmt.Sprintf("%s://%s%s", req.URL.Scheme, req.URL.Host, req.URL.Path)
+
+	if c.UserAgent != "" {
+		req.Header.Add("User-Agent", c.UserAgent)
+	}
+	if auth := c.authenticator(); auth != nil {
+		if err := auth.Authenticate(req); err != nil {
+			return nil, errors.Wrap(err, "Error authenticating request")
+		}
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, errors.Wrapf(redactError(err), "HTTP request failure on %s", noParameterURL)
 	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer func() {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}()
+		return nil, c.makeHTTPClientError(resp)
+	}
+	return resp, nil
+}
+
+// send makes the HTTP request, retrying it according to the client's RetryPolicy
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	attempts := c.RetryPolicy.attempts()
+	for attempt := 1; ; attempt++ {
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(req.Context()); err != nil {
+				return nil, errors.Wrap(err, "cancelled while waiting on rate limiter")
+			}
+		}
+		attemptReq, cancel := c.withTimeout(req)
+		resp, err := c.Client.Do(attemptReq)
+		// A request body can only be sent again if we are able to rewind it
+		rewindable := req.Body == nil || req.GetBody != nil
+		if attempt >= attempts || !rewindable || !c.RetryPolicy.shouldRetry(req, resp, err) {
+			if resp == nil {
+				cancel()
+				return resp, err
+			}
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, err
+		}
+
+		wait := c.RetryPolicy.backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+			c.debugLog("[targetprocess] %s %s returned %d, retrying in %s (attempt %d of %d)", req.Method, req.URL.Path, resp.StatusCode, wait, attempt+1, attempts)
+		} else {
+			c.debugLog("[targetprocess] %s %s failed: %s, retrying in %s (attempt %d of %d)", req.Method, req.URL.Path, redactError(err), wait, attempt+1, attempts)
+		}
+		cancel()
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, errors.Wrap(err, "cancelled while waiting to retry")
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, errors.Wrap(err, "could not rewind request body for retry")
+			}
+			req.Body = body
+		}
+	}
+}
+
+// withTimeout applies the client's Timeout to a single attempt of a request. The returned
+// cancel func must be called once the response body is no longer needed.
+func (c *Client) withTimeout(req *http.Request) (*http.Request, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return req, func() {}
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), c.Timeout)
+	return req.WithContext(ctx), cancel
+}
+
+// cancelOnClose releases the context of a request once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func (c *Client) defaultParams(v url.Values) url.Values {
 	v.Set("format", "json")
 	v.Set("resultFormat", "json")
 	return v
@@ -235,12 +404,12 @@ func (c *Client) defaultParams(v url.Values) url.Values {
 
 func (c *Client) debugLog(format string, args ...interface{}) {
 	if c.Logger != nil {
-		c.Logger.Debugf(format, args...)
+		c.Logger.Debugf("%s", c.redactSecrets(fmt.Sprintf(format, args...)))
 	}
 }
 
 func (c *Client) infoLog(format string, args ...interface{}) { // nolint:golint,unused
 	if c.Logger != nil {
-		c.Logger.Infof(format, args...)
+		c.Logger.Infof("%s", c.redactSecrets(fmt.Sprintf(format, args...)))
 	}
 }
@@ -18,12 +18,15 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -45,18 +48,16 @@ type genericResponse struct {
 func newMockClient(handler http.Handler, account, token string) (*Client, func()) {
 	s := httptest.NewTLSServer(handler)
 
-	defaultClient = &http.Client{
-		Transport: &http.Transport{
-			DialContext: func(_ context.Context, network, _ string) (net.Conn, error) {
-				return net.Dial(network, s.Listener.Addr().String())
-			},
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
+	transport := &http.Transport{
+		DialContext: func(_ context.Context, network, _ string) (net.Conn, error) {
+			return net.Dial(network, s.Listener.Addr().String())
+		},
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
 		},
 	}
 
-	client, _ := NewClient(account, token)
+	client, _ := NewClientWithOptions(WithAccount(account), WithToken(token), WithTransport(transport))
 
 	return client, s.Close
 }
@@ -203,3 +204,134 @@ func TestPost(t *testing.T) {
 		}
 	}
 }
+
+func TestNewClientOwnsHTTPClient(t *testing.T) {
+	defaultTimeout := http.DefaultClient.Timeout
+	client, err := NewClient("example", "abcd1234")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultTimeout, http.DefaultClient.Timeout)
+	assert.NotSame(t, http.DefaultClient, client.Client)
+
+	other, err := NewClient("example", "abcd1234")
+	assert.NoError(t, err)
+	assert.NotSame(t, client.Client, other.Client)
+
+	callerClient := &http.Client{}
+	transport := &http.Transport{}
+	client, err = NewClientWithOptions(WithAccount("example"), WithHTTPClient(callerClient), WithTransport(transport))
+	assert.NoError(t, err)
+	assert.Nil(t, callerClient.Transport)
+	assert.Equal(t, transport, client.Client.Transport)
+}
+
+func TestTimeout(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		_, _ = w.Write([]byte(okResponse))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+	mockClient.RetryPolicy = nil
+	mockClient.Timeout = 20 * time.Millisecond
+
+	start := time.Now()
+	err := mockClient.Get(new(genericResponse), "Users", nil)
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestGetCtxCancelled(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent with a cancelled context")
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := mockClient.GetCtx(ctx, new(genericResponse), "Users", nil)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestGetUserStoriesCtxCancelMidPaging(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests int32
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := atomic.AddInt32(&requests, 1)
+		if page == 2 {
+			// the caller goes away while the second page is being served
+			cancel()
+		}
+		_, _ = fmt.Fprintf(w, `{"Items": [{"Id": %d}], "Next": "https://example.tpondemand.com/api/v2/UserStories?take=1&skip=%d"}`, page, page)
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	stories, err := mockClient.GetUserStoriesCtx(ctx, true, MaxPerPage(1))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, len(stories) <= 2)
+	assert.True(t, atomic.LoadInt32(&requests) <= 2)
+}
+
+func TestNewUserStoryCtxCancelled(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent with a cancelled context")
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewUserStoryCtx(ctx, mockClient, "Story", "", "Project")
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	us := UserStory{client: mockClient}
+	assert.True(t, errors.Is(us.SetTeamCtx(ctx, "Team"), context.Canceled))
+	assert.True(t, errors.Is(us.SetFeatureCtx(ctx, "Feature"), context.Canceled))
+
+	_, err = Team{client: mockClient}.NewUserStoryCtx(ctx, "Story", "", "Project")
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = Project{client: mockClient}.NewUserStoryCtx(ctx, "Story", "", "Team")
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = Feature{client: mockClient}.NewUserStoryCtx(ctx, "Story", "", "Project")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestGetByID(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		switch r.URL.Path {
+		case "/api/v1/UserStories/42":
+			_, _ = w.Write([]byte(`{"Id": 42, "Name": "Story", "Project": {"Id": 7, "Name": "Project"}}`))
+		case "/api/v1/Projects/7":
+			_, _ = w.Write([]byte(`{"Id": 7, "Name": "Project", "Process": {"Id": 3}}`))
+		default:
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(`{"Status": "NotFound", "Message": "not found"}`))
+		}
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	us, err := mockClient.GetUserStoryByID(42)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(42), us.ID)
+	assert.Equal(t, "Project", us.Project.Name)
+	assert.Equal(t, mockClient, us.client)
+
+	p, err := mockClient.GetProjectByID(7)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), p.Process.ID)
+	assert.Equal(t, mockClient, p.client)
+
+	_, err = mockClient.GetFeatureByID(1)
+	assert.Error(t, err)
+	assert.True(t, IsNotFound(err))
+}
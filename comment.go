@@ -0,0 +1,214 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Comment matches up with a targetprocess Comment. Description is HTML.
+type Comment struct {
+	client *Client
+
+	ID          int32     `json:"Id,omitempty"`
+	Description string    `json:",omitempty"`
+	CreateDate  *DateTime `json:",omitempty"`
+	ParentID    int32     `json:"ParentId,omitempty"`
+	Owner       *User     `json:",omitempty"`
+	General     *General  `json:",omitempty"`
+}
+
+// CommentResponse is a representation of the http response for a group of Comments
+type CommentResponse struct {
+	Items []Comment
+	Next  string
+	Prev  string
+}
+
+// CommentThread is a Comment along with the replies to it
+type CommentThread struct {
+	Comment
+	Replies []*CommentThread
+}
+
+// GetComments will return all comments left on the entity with the given ID, replies included
+func (c *Client) GetComments(entityID int32, filters ...QueryFilter) ([]Comment, error) {
+	return c.GetCommentsCtx(c.context(), entityID, filters...)
+}
+
+// GetCommentsCtx is GetComments with a context that can cancel the requests
+func (c *Client) GetCommentsCtx(ctx context.Context, entityID int32, filters ...QueryFilter) ([]Comment, error) {
+	var ret []Comment
+	filters = append([]QueryFilter{WhereExpr(Field("General.Id").Eq(entityID))}, filters...)
+	it := c.NewIteratorCtx(ctx, "Comments", filters...)
+	for it.Next() {
+		item := Comment{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetCommentThreads will return the comments left on the entity with the given ID with replies nested
+// under the comment they answer. Threads are in the order their first comment was returned.
+func (c *Client) GetCommentThreads(entityID int32, filters ...QueryFilter) ([]*CommentThread, error) {
+	return c.GetCommentThreadsCtx(c.context(), entityID, filters...)
+}
+
+// GetCommentThreadsCtx is GetCommentThreads with a context that can cancel the requests
+func (c *Client) GetCommentThreadsCtx(ctx context.Context, entityID int32, filters ...QueryFilter) ([]*CommentThread, error) {
+	comments, err := c.GetCommentsCtx(ctx, entityID, filters...)
+	if err != nil {
+		return nil, err
+	}
+	return threadComments(comments), nil
+}
+
+// threadComments nests every comment under its parent. Replies whose parent is not in the list are
+// treated as threads of their own.
+func threadComments(comments []Comment) []*CommentThread {
+	threads := make(map[int32]*CommentThread, len(comments))
+	for _, comment := range comments {
+		threads[comment.ID] = &CommentThread{Comment: comment}
+	}
+	var ret []*CommentThread
+	for _, comment := range comments {
+		thread := threads[comment.ID]
+		if parent, ok := threads[comment.ParentID]; ok && comment.ParentID != 0 {
+			parent.Replies = append(parent.Replies, thread)
+			continue
+		}
+		ret = append(ret, thread)
+	}
+	return ret
+}
+
+// AddComment leaves a comment on the entity with the given ID. text is HTML and is sent as is.
+func (c *Client) AddComment(entityID int32, text string) (Comment, error) {
+	return c.AddCommentCtx(c.context(), entityID, text)
+}
+
+// AddCommentCtx is AddComment with a context that can cancel the request
+func (c *Client) AddCommentCtx(ctx context.Context, entityID int32, text string) (Comment, error) {
+	return Comment{
+		client:      c,
+		Description: text,
+		General:     &General{ID: entityID},
+	}.CreateCtx(ctx)
+}
+
+// Reply answers the Comment that this method is built off of
+func (cm Comment) Reply(text string) (Comment, error) {
+	return cm.ReplyCtx(cm.client.context(), text)
+}
+
+// ReplyCtx is Reply with a context that can cancel the request
+func (cm Comment) ReplyCtx(ctx context.Context, text string) (Comment, error) {
+	if cm.ID == 0 || cm.General == nil {
+		return Comment{}, fmt.Errorf("Comment %d has not been created yet", cm.ID)
+	}
+	return Comment{
+		client:      cm.client,
+		Description: text,
+		ParentID:    cm.ID,
+		General:     &General{ID: cm.General.ID},
+	}.CreateCtx(ctx)
+}
+
+// Create takes a Comment struct and crafts a POST to make it so in TP, returning the Comment
+// as it was created
+func (cm Comment) Create() (Comment, error) {
+	return cm.CreateCtx(cm.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the request
+func (cm Comment) CreateCtx(ctx context.Context) (Comment, error) {
+	client := cm.client
+	if client == nil {
+		return Comment{}, fmt.Errorf("Comment has no client, get it from a Client method")
+	}
+	body, err := json.Marshal(cm)
+	if err != nil {
+		return Comment{}, errors.Wrap(err, "error marshaling POST body for Comment")
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Attempting to POST Comment: %s", body))
+	ret := Comment{}
+	err = client.PostCtx(ctx, &ret, "Comments", nil, body)
+	if err != nil {
+		return Comment{}, errors.Wrap(err, "error POSTing Comment")
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Comment created. ID: %d", ret.ID))
+	ret.client = client
+	return ret, nil
+}
+
+// AddComment leaves a comment on the UserStory. text is HTML and is sent as is.
+func (us UserStory) AddComment(text string) (Comment, error) {
+	return us.AddCommentCtx(us.client.context(), text)
+}
+
+// AddCommentCtx is AddComment with a context that can cancel the request
+func (us UserStory) AddCommentCtx(ctx context.Context, text string) (Comment, error) {
+	if us.client == nil || us.ID == 0 {
+		return Comment{}, fmt.Errorf("UserStory %d has no client or has not been created yet", us.ID)
+	}
+	return us.client.AddCommentCtx(ctx, us.ID, text)
+}
+
+// AddComment leaves a comment on the Feature. text is HTML and is sent as is.
+func (f Feature) AddComment(text string) (Comment, error) {
+	return f.AddCommentCtx(f.client.context(), text)
+}
+
+// AddCommentCtx is AddComment with a context that can cancel the request
+func (f Feature) AddCommentCtx(ctx context.Context, text string) (Comment, error) {
+	if f.client == nil || f.ID == 0 {
+		return Comment{}, fmt.Errorf("Feature %d has no client or has not been created yet", f.ID)
+	}
+	return f.client.AddCommentCtx(ctx, f.ID, text)
+}
+
+// AddComment leaves a comment on the Bug. text is HTML and is sent as is.
+func (b Bug) AddComment(text string) (Comment, error) {
+	return b.AddCommentCtx(b.client.context(), text)
+}
+
+// AddCommentCtx is AddComment with a context that can cancel the request
+func (b Bug) AddCommentCtx(ctx context.Context, text string) (Comment, error) {
+	if b.client == nil || b.ID == 0 {
+		return Comment{}, fmt.Errorf("Bug %d has no client or has not been created yet", b.ID)
+	}
+	return b.client.AddCommentCtx(ctx, b.ID, text)
+}
+
+// AddComment leaves a comment on the Task. text is HTML and is sent as is.
+func (t Task) AddComment(text string) (Comment, error) {
+	return t.AddCommentCtx(t.client.context(), text)
+}
+
+// AddCommentCtx is AddComment with a context that can cancel the request
+func (t Task) AddCommentCtx(ctx context.Context, text string) (Comment, error) {
+	if t.client == nil || t.ID == 0 {
+		return Comment{}, fmt.Errorf("Task %d has no client or has not been created yet", t.ID)
+	}
+	return t.client.AddCommentCtx(ctx, t.ID, text)
+}
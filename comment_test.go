@@ -0,0 +1,82 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentReply(t *testing.T) {
+	var bodies []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/Comments/", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			_, _ = w.Write([]byte(`{"Id": 10, "Description": "<p>CI passed</p>", "General": {"Id": 42, "ResourceType": "UserStory"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Id": 11, "ParentId": 10, "General": {"Id": 42}}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	comment, err := UserStory{client: mockClient, ID: 42}.AddComment("<p>CI passed</p>")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), comment.ID)
+	assert.Equal(t, "UserStory", comment.General.ResourceType)
+
+	reply, err := comment.Reply("thanks")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), reply.ParentID)
+
+	assert.JSONEq(t, `{"Description": "<p>CI passed</p>", "General": {"Id": 42}}`, bodies[0])
+	assert.JSONEq(t, `{"Description": "thanks", "ParentId": 10, "General": {"Id": 42}}`, bodies[1])
+
+	_, err = Comment{client: mockClient}.Reply("not created")
+	assert.Error(t, err)
+	_, err = Bug{client: mockClient}.AddComment("not created")
+	assert.Error(t, err)
+}
+
+func TestGetCommentThreads(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Comments/", r.URL.Path)
+		assert.Equal(t, "General.Id == 42", r.URL.Query().Get("where"))
+		_, _ = w.Write([]byte(`{"items": [
+			{"id": 1, "description": "first"},
+			{"id": 2, "description": "reply", "parentId": 1},
+			{"id": 3, "description": "second"},
+			{"id": 4, "description": "nested reply", "parentId": 2},
+			{"id": 5, "description": "orphan reply", "parentId": 99}
+		]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	threads, err := mockClient.GetCommentThreads(42)
+	assert.NoError(t, err)
+	assert.Len(t, threads, 3)
+	assert.Equal(t, "first", threads[0].Description)
+	assert.Equal(t, "reply", threads[0].Replies[0].Description)
+	assert.Equal(t, "nested reply", threads[0].Replies[0].Replies[0].Description)
+	assert.Equal(t, "second", threads[1].Description)
+	assert.Equal(t, int32(5), threads[2].ID)
+}
@@ -15,6 +15,7 @@
 package targetprocess
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -40,11 +41,16 @@ type CustomFieldResponse struct {
 
 // GetCustomField will return a CustomField object from a name. Returns an error if not found.
 func (c *Client) GetCustomField(name string) (CustomField, error) {
+	return c.GetCustomFieldCtx(c.context(), name)
+}
+
+// GetCustomFieldCtx is GetCustomField with a context that can cancel the requests
+func (c *Client) GetCustomFieldCtx(ctx context.Context, name string) (CustomField, error) {
 	c.debugLog(fmt.Sprintf("[targetprocess] attempting to get CustomField: %s", name))
 	ret := CustomField{}
 	out := CustomFieldResponse{}
-	err := c.Get(&out, "CustomField", nil,
-		Where(fmt.Sprintf("Name == '%s'", name)),
+	err := c.GetCtx(ctx, &out, "CustomField", nil,
+		WhereExpr(Field("Name").Eq(name)),
 		First(),
 	)
 	if err != nil {
@@ -57,3 +63,20 @@ func (c *Client) GetCustomField(name string) (CustomField, error) {
 	ret.client = c
 	return ret, nil
 }
+
+// GetCustomFieldByID will return the CustomField with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetCustomFieldByID(id int32) (CustomField, error) {
+	return c.GetCustomFieldByIDCtx(c.context(), id)
+}
+
+// GetCustomFieldByIDCtx is GetCustomFieldByID with a context that can cancel the request
+func (c *Client) GetCustomFieldByIDCtx(ctx context.Context, id int32) (CustomField, error) {
+	ret := CustomField{}
+	err := c.GetByIDCtx(ctx, &ret, "CustomFields", id)
+	if err != nil {
+		return CustomField{}, errors.Wrap(err, fmt.Sprintf("error getting CustomField with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// msDate matches the /Date(1600000000000+0300)/ format of the v1 API: milliseconds since the
+// epoch, followed by the offset of the time zone the date was recorded in
+var msDate = regexp.MustCompile(`^/Date\((-?\d+)([+-])?(\d{2})?(\d{2})?\)/$`)
+
+// isoLayouts are the layouts of the ISO 8601 dates returned by the v2 API, tried in order.
+// Dates without an offset are in UTC.
+var isoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// DateTime is a Targetprocess date. It is read from both the /Date(1600000000000+0300)/ format
+// of the v1 API and the ISO 8601 format of the v2 API, keeping the time zone offset it was sent
+// with, and null is read as the zero DateTime.
+//
+// It is sent in the /Date()/ format. A zero DateTime is sent as null, use a nil *DateTime
+// to leave the field out instead. Time, IsZero and String can be called on a nil *DateTime, which is
+// what a date field holds when the API sent null or it wasn't selected.
+type DateTime struct {
+	t time.Time
+}
+
+// NewDateTime returns the DateTime for t
+func NewDateTime(t time.Time) *DateTime {
+	return &DateTime{t: t}
+}
+
+// Time returns the DateTime as a time.Time, in the time zone offset it was received with.
+// It is the zero time.Time when d is nil.
+func (d *DateTime) Time() time.Time {
+	if d == nil {
+		return time.Time{}
+	}
+	return d.t
+}
+
+// IsZero tells if the DateTime is unset or nil
+func (d *DateTime) IsZero() bool {
+	return d.Time().IsZero()
+}
+
+// String returns the DateTime in the RFC 3339 format, or an empty string when it is unset or nil
+func (d *DateTime) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.t.Format(time.RFC3339)
+}
+
+// MarshalJSON sends the DateTime in the /Date(1600000000000+0300)/ format. Unlike the other methods
+// it has a value receiver on purpose, so a DateTime that isn't addressable, like a field of a struct
+// passed by value to json.Marshal, is still sent in that format. A nil *DateTime is sent as null.
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.t.IsZero() {
+		return []byte("null"), nil
+	}
+	_, offset := d.t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	ms := d.t.UnixNano() / int64(time.Millisecond)
+	return json.Marshal(fmt.Sprintf("/Date(%d%c%02d%02d)/", ms, sign, offset/3600, offset%3600/60))
+}
+
+// UnmarshalJSON reads the DateTime from the /Date()/ or ISO 8601 format, or null
+func (d *DateTime) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		d.t = time.Time{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("DateTime must be a string or null, got %s", b)
+	}
+	t, err := parseDateTime(s)
+	if err != nil {
+		return err
+	}
+	d.t = t
+	return nil
+}
+
+// parseDateTime parses a date in either of the formats Targetprocess sends them in
+func parseDateTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if m := msDate.FindStringSubmatch(s); m != nil {
+		ms, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid DateTime %s: %v", s, err)
+		}
+		t := time.Unix(0, ms*int64(time.Millisecond)).UTC()
+		if m[2] == "" {
+			return t, nil
+		}
+		hours, _ := strconv.Atoi(m[3])
+		minutes, _ := strconv.Atoi(m[4])
+		offset := hours*3600 + minutes*60
+		if m[2] == "-" {
+			offset = -offset
+		}
+		return t.In(time.FixedZone("", offset)), nil
+	}
+	for _, layout := range isoLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid DateTime %s, expected /Date(ms+hhmm)/ or ISO 8601", s)
+}
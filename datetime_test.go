@@ -0,0 +1,127 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateTimeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		json       string
+		want       time.Time
+		wantOffset int
+		wantErr    bool
+	}{
+		{
+			name:       "v1 with offset",
+			json:       `"/Date(1600000000000+0300)/"`,
+			want:       time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC),
+			wantOffset: 3 * 3600,
+		},
+		{
+			name:       "v1 with negative offset",
+			json:       `"/Date(1600000000000-0530)/"`,
+			want:       time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC),
+			wantOffset: -(5*3600 + 30*60),
+		},
+		{
+			name: "v1 without offset",
+			json: `"/Date(1600000000000)/"`,
+			want: time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC),
+		},
+		{
+			name:       "v2 with offset",
+			json:       `"2020-09-13T15:26:40.5+03:00"`,
+			want:       time.Date(2020, 9, 13, 12, 26, 40, 5e8, time.UTC),
+			wantOffset: 3 * 3600,
+		},
+		{
+			name: "v2 without offset",
+			json: `"2020-09-13T12:26:40"`,
+			want: time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC),
+		},
+		{
+			name: "null",
+			json: `null`,
+		},
+		{
+			name:    "not a date",
+			json:    `"tomorrow"`,
+			wantErr: true,
+		},
+		{
+			name:    "not a string",
+			json:    `1600000000000`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DateTime{}
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.want.Equal(got.Time()), "got %s", got.Time())
+			_, offset := got.Time().Zone()
+			assert.Equal(t, tt.wantOffset, offset)
+		})
+	}
+}
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	us := UserStory{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"CreateDate": "/Date(1600000000000+0300)/", "ModifyDate": null}`), &us))
+	assert.Equal(t, "2020-09-13T15:26:40+03:00", us.CreateDate.String())
+	assert.True(t, us.ModifyDate == nil || us.ModifyDate.IsZero())
+
+	body, err := json.Marshal(UserStory{Name: "dated", StartDate: us.CreateDate})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Name": "dated", "StartDate": "/Date(1600000000000+0300)/"}`, string(body))
+
+	body, err = json.Marshal(struct{ Date DateTime }{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Date": null}`, string(body))
+}
+
+func TestDateTimeNull(t *testing.T) {
+	us := UserStory{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"Id": 1, "ModifyDate": null}`), &us))
+	assert.Nil(t, us.ModifyDate)
+	assert.Nil(t, us.CreateDate)
+	assert.NotPanics(t, func() {
+		assert.True(t, us.ModifyDate.Time().IsZero())
+		assert.True(t, us.CreateDate.IsZero())
+		assert.Equal(t, "", us.ModifyDate.String())
+	})
+	assert.Equal(t, "", fmt.Sprint(us.ModifyDate))
+}
+
+func TestDateTimeLiteral(t *testing.T) {
+	d := DateTime{}
+	assert.NoError(t, json.Unmarshal([]byte(`"/Date(1600000000000+0300)/"`), &d))
+	values, err := applyFilters(APIv2, nil, []QueryFilter{WhereExpr(Field("CreateDate").Gt(d), Field("EndDate").Lt(&d))})
+	assert.NoError(t, err)
+	assert.Equal(t, "CreateDate > '2020-09-13T15:26:40+03:00' and EndDate < '2020-09-13T15:26:40+03:00'", values.Get("where"))
+}
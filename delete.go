@@ -0,0 +1,136 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// DeleteOption configures a call to DeleteEntities
+type DeleteOption func(o *deleteOptions)
+
+type deleteOptions struct {
+	confirmed bool
+}
+
+// ConfirmDelete must be passed to DeleteEntities for it to delete anything. It exists so
+// a bulk delete can never be triggered by accident.
+func ConfirmDelete() DeleteOption {
+	return func(o *deleteOptions) {
+		o.confirmed = true
+	}
+}
+
+// DeleteResult is the outcome of deleting a single entity with DeleteEntities
+type DeleteResult struct {
+	ID  int32
+	Err error
+}
+
+// Delete will delete the entity of entityType (ex. UserStories) with the given ID
+func (c *Client) Delete(entityType string, id int32) error {
+	return c.DeleteCtx(c.context(), entityType, id)
+}
+
+// DeleteCtx is Delete with a context that can cancel the request
+func (c *Client) DeleteCtx(ctx context.Context, entityType string, id int32) error {
+	if id == 0 {
+		return fmt.Errorf("cannot delete %s without an id", entityType)
+	}
+	c.debugLog(fmt.Sprintf("[targetprocess] Attempting to DELETE %s %d", entityType, id))
+	err := c.write(ctx, "DELETE", nil, fmt.Sprintf("%s/%d", entityType, id), nil, nil)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error deleting %s %d", entityType, id))
+	}
+	return nil
+}
+
+// DeleteEntities will delete every entity of entityType (ex. UserStories) with one of the given IDs.
+// Nothing is deleted unless ConfirmDelete is passed in the options.
+//
+// The bulk endpoint of the API is tried first. It doesn't report which entities failed, so when the
+// bulk request fails, or isn't available for entityType, the entities are deleted one by one to get
+// the outcome of each. A result is returned for every ID, and the error is not nil if any of them
+// could not be deleted.
+func (c *Client) DeleteEntities(entityType string, ids []int32, opts ...DeleteOption) ([]DeleteResult, error) {
+	return c.DeleteEntitiesCtx(c.context(), entityType, ids, opts...)
+}
+
+// DeleteEntitiesCtx is DeleteEntities with a context that can cancel the requests
+func (c *Client) DeleteEntitiesCtx(ctx context.Context, entityType string, ids []int32, opts ...DeleteOption) ([]DeleteResult, error) {
+	o := deleteOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.confirmed {
+		return nil, errors.New("refusing to delete entities without the ConfirmDelete option")
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	for _, id := range ids {
+		if id == 0 {
+			return nil, fmt.Errorf("cannot delete %s without an id", entityType)
+		}
+	}
+
+	results := make([]DeleteResult, len(ids))
+	if err := c.bulkDelete(ctx, entityType, ids); err != nil {
+		c.debugLog(fmt.Sprintf("[targetprocess] bulk delete of %s failed, deleting one by one: %s", entityType, err))
+		for i, id := range ids {
+			results[i] = DeleteResult{ID: id, Err: c.DeleteCtx(ctx, entityType, id)}
+		}
+	} else {
+		for i, id := range ids {
+			results[i] = DeleteResult{ID: id}
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("failed to delete %d of %d %s", failed, len(ids), entityType)
+	}
+	return results, nil
+}
+
+// bulkDelete deletes all ids in a single request
+func (c *Client) bulkDelete(ctx context.Context, entityType string, ids []int32) error {
+	items := make([]struct {
+		ID int32 `json:"Id"`
+	}, len(ids))
+	for i, id := range ids {
+		items[i].ID = id
+	}
+	body, err := json.Marshal(items)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error marshaling bulk DELETE body for %s", entityType))
+	}
+
+	c.debugLog(fmt.Sprintf("[targetprocess] Attempting to bulk DELETE %s: %v", entityType, ids))
+	err = c.write(ctx, "DELETE", nil, entityType+"/bulk", nil, body)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error bulk deleting %s", entityType))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteEntities(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []DeleteOption
+		bulkStatus    int
+		missingID     string
+		wantRequests  []string
+		wantFailedIDs []int32
+		wantErr       bool
+	}{
+		{
+			name:    "not confirmed",
+			wantErr: true,
+		},
+		{
+			name:         "bulk",
+			opts:         []DeleteOption{ConfirmDelete()},
+			bulkStatus:   200,
+			wantRequests: []string{"/api/v1/UserStories/bulk"},
+		},
+		{
+			name:          "one by one when bulk is unavailable",
+			opts:          []DeleteOption{ConfirmDelete()},
+			bulkStatus:    405,
+			missingID:     "2",
+			wantRequests:  []string{"/api/v1/UserStories/bulk", "/api/v1/UserStories/1", "/api/v1/UserStories/2", "/api/v1/UserStories/3"},
+			wantFailedIDs: []int32{2},
+			wantErr:       true,
+		},
+		{
+			name:          "one by one when bulk fails",
+			opts:          []DeleteOption{ConfirmDelete()},
+			bulkStatus:    400,
+			missingID:     "3",
+			wantRequests:  []string{"/api/v1/UserStories/bulk", "/api/v1/UserStories/1", "/api/v1/UserStories/2", "/api/v1/UserStories/3"},
+			wantFailedIDs: []int32{3},
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				requests []string
+			)
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requests = append(requests, r.URL.Path)
+				mu.Unlock()
+				assert.Equal(t, "DELETE", r.Method)
+				switch r.URL.Path {
+				case "/api/v1/UserStories/bulk":
+					body, _ := ioutil.ReadAll(r.Body)
+					assert.JSONEq(t, `[{"Id": 1}, {"Id": 2}, {"Id": 3}]`, string(body))
+					w.WriteHeader(tt.bulkStatus)
+				case "/api/v1/UserStories/" + tt.missingID:
+					w.WriteHeader(404)
+				}
+			})
+			mockClient, teardown := newMockClient(h, "example", "abcd1234")
+			defer teardown()
+
+			results, err := mockClient.DeleteEntities("UserStories", []int32{1, 2, 3}, tt.opts...)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRequests, requests)
+
+			var failed []int32
+			for _, result := range results {
+				if result.Err != nil {
+					failed = append(failed, result.ID)
+				}
+			}
+			assert.Equal(t, tt.wantFailedIDs, failed)
+		})
+	}
+}
+
+func TestUserStoryDelete(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/api/v1/UserStories/42", r.URL.Path)
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	assert.NoError(t, UserStory{client: mockClient, ID: 42}.Delete())
+	assert.Error(t, UserStory{client: mockClient}.Delete())
+	assert.Error(t, UserStory{ID: 42}.Delete())
+}
@@ -14,6 +14,13 @@
 
 package targetprocess
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
 // EntityState contains metadata for the state of an Entity. Collection of EntityStates
 // form Workflow for Entity. For example, Bug has four EntityStates by default: Open, Fixed, Invalid and Done
 type EntityState struct {
@@ -26,6 +33,14 @@ type EntityState struct {
 	IsFinal           bool         `json:",omitempty"`
 	IsPlanned         bool         `json:",omitempty"`
 	IsCommentRequired bool         `json:",omitempty"`
+	EntityType        *EntityType  `json:",omitempty"`
+	Workflow          *Workflow    `json:",omitempty"`
+}
+
+// EntityType is the type of an entity, ex. UserStory or Bug
+type EntityType struct {
+	ID   int32  `json:"Id,omitempty"`
+	Name string `json:",omitempty"`
 }
 
 // EntityStateResponse is a representation of the http response for a group of EntityStates
@@ -37,22 +52,35 @@ type EntityStateResponse struct {
 
 // GetEntityStates will return all EntityStates
 func (c *Client) GetEntityStates(filters ...QueryFilter) ([]EntityState, error) {
-	var ret []EntityState
-	out := EntityStateResponse{}
+	return c.GetEntityStatesCtx(c.context(), filters...)
+}
 
-	err := c.Get(&out, "EntityState", nil, filters...)
-	if err != nil {
-		return nil, err
-	}
-	ret = append(ret, out.Items...)
-	for out.Next != "" {
-		innerOut := EntityStateResponse{}
-		err := c.GetNext(&innerOut, out.Next)
-		if err != nil {
+// GetEntityStatesCtx is GetEntityStates with a context that can cancel the requests
+func (c *Client) GetEntityStatesCtx(ctx context.Context, filters ...QueryFilter) ([]EntityState, error) {
+	var ret []EntityState
+	it := c.NewIteratorCtx(ctx, "EntityState", filters...)
+	for it.Next() {
+		item := EntityState{}
+		if err := it.Decode(&item); err != nil {
 			return ret, err
 		}
-		ret = append(ret, innerOut.Items...)
-		out = innerOut
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetEntityStateByID will return the EntityState with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetEntityStateByID(id int32) (EntityState, error) {
+	return c.GetEntityStateByIDCtx(c.context(), id)
+}
+
+// GetEntityStateByIDCtx is GetEntityStateByID with a context that can cancel the request
+func (c *Client) GetEntityStateByIDCtx(ctx context.Context, id int32) (EntityState, error) {
+	ret := EntityState{}
+	err := c.GetByIDCtx(ctx, &ret, "EntityStates", id)
+	if err != nil {
+		return EntityState{}, errors.Wrap(err, fmt.Sprintf("error getting EntityState with id %d", id))
 	}
 	return ret, nil
 }
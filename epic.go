@@ -0,0 +1,299 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// PortfolioEpic matches up with a targetprocess PortfolioEpic, the top of the
+// PortfolioEpic -> Epic -> Feature -> UserStory hierarchy
+type PortfolioEpic struct {
+	client *Client
+
+	ID              int32         `json:"Id,omitempty"`
+	Name            string        `json:",omitempty"`
+	Description     string        `json:",omitempty"`
+	Effort          float32       `json:",omitempty"`
+	NumericPriority float32       `json:",omitempty"`
+	Project         *Project      `json:",omitempty"`
+	CustomFields    []CustomField `json:",omitempty"`
+	EntityState     *EntityState  `json:",omitempty"`
+}
+
+// PortfolioEpicResponse is a representation of the http response for a group of PortfolioEpics
+type PortfolioEpicResponse struct {
+	Items []PortfolioEpic
+	Next  string
+	Prev  string
+}
+
+// Epic matches up with a targetprocess Epic. Epics group Features together.
+type Epic struct {
+	client *Client
+
+	ID              int32          `json:"Id,omitempty"`
+	Name            string         `json:",omitempty"`
+	Description     string         `json:",omitempty"`
+	Effort          float32        `json:",omitempty"`
+	NumericPriority float32        `json:",omitempty"`
+	Project         *Project       `json:",omitempty"`
+	CustomFields    []CustomField  `json:",omitempty"`
+	EntityState     *EntityState   `json:",omitempty"`
+	PortfolioEpic   *PortfolioEpic `json:",omitempty"`
+}
+
+// EpicResponse is a representation of the http response for a group of Epics
+type EpicResponse struct {
+	Items []Epic
+	Next  string
+	Prev  string
+}
+
+// NewPortfolioEpic creates a PortfolioEpic struct with the required fields of
+// name, description, and project.
+func NewPortfolioEpic(c *Client, name, description, project string) (PortfolioEpic, error) {
+	return NewPortfolioEpicCtx(c.context(), c, name, description, project)
+}
+
+// NewPortfolioEpicCtx is NewPortfolioEpic with a context that can cancel the requests
+func NewPortfolioEpicCtx(ctx context.Context, c *Client, name, description, project string) (PortfolioEpic, error) {
+	pe := PortfolioEpic{
+		client:      c,
+		Name:        name,
+		Description: description,
+	}
+	c.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Project: %s", project))
+	p, err := c.GetProjectCtx(ctx, project)
+	if err != nil {
+		return PortfolioEpic{}, err
+	}
+	pe.Project = &p
+	return pe, nil
+}
+
+// NewEpic creates an Epic struct with the required fields of
+// name, description, and project.
+func NewEpic(c *Client, name, description, project string) (Epic, error) {
+	return NewEpicCtx(c.context(), c, name, description, project)
+}
+
+// NewEpicCtx is NewEpic with a context that can cancel the requests
+func NewEpicCtx(ctx context.Context, c *Client, name, description, project string) (Epic, error) {
+	e := Epic{
+		client:      c,
+		Name:        name,
+		Description: description,
+	}
+	c.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Project: %s", project))
+	p, err := c.GetProjectCtx(ctx, project)
+	if err != nil {
+		return Epic{}, err
+	}
+	e.Project = &p
+	return e, nil
+}
+
+// NewEpic will make an Epic in the project of the PortfolioEpic that this method is built off of
+func (pe PortfolioEpic) NewEpic(name, description string) (Epic, error) {
+	if pe.ID == 0 {
+		return Epic{}, fmt.Errorf("PortfolioEpic %s has not been created yet", pe.Name)
+	}
+	return Epic{
+		client:        pe.client,
+		Name:          name,
+		Description:   description,
+		Project:       pe.Project,
+		PortfolioEpic: &PortfolioEpic{ID: pe.ID},
+	}, nil
+}
+
+// NewFeature will make a Feature in the project of the Epic that this method is built off of
+func (e Epic) NewFeature(name, description string) (Feature, error) {
+	if e.ID == 0 {
+		return Feature{}, fmt.Errorf("Epic %s has not been created yet", e.Name)
+	}
+	return Feature{
+		client:      e.client,
+		Name:        name,
+		Description: description,
+		Project:     e.Project,
+		Epic:        &Epic{ID: e.ID},
+	}, nil
+}
+
+// GetPortfolioEpics will return all portfolio epics
+func (c *Client) GetPortfolioEpics(filters ...QueryFilter) ([]PortfolioEpic, error) {
+	return c.GetPortfolioEpicsCtx(c.context(), filters...)
+}
+
+// GetPortfolioEpicsCtx is GetPortfolioEpics with a context that can cancel the requests
+func (c *Client) GetPortfolioEpicsCtx(ctx context.Context, filters ...QueryFilter) ([]PortfolioEpic, error) {
+	var ret []PortfolioEpic
+	it := c.NewIteratorCtx(ctx, "PortfolioEpics", filters...)
+	for it.Next() {
+		item := PortfolioEpic{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetPortfolioEpicByID will return the PortfolioEpic with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetPortfolioEpicByID(id int32) (PortfolioEpic, error) {
+	return c.GetPortfolioEpicByIDCtx(c.context(), id)
+}
+
+// GetPortfolioEpicByIDCtx is GetPortfolioEpicByID with a context that can cancel the request
+func (c *Client) GetPortfolioEpicByIDCtx(ctx context.Context, id int32) (PortfolioEpic, error) {
+	ret := PortfolioEpic{}
+	err := c.GetByIDCtx(ctx, &ret, "PortfolioEpics", id)
+	if err != nil {
+		return PortfolioEpic{}, errors.Wrap(err, fmt.Sprintf("error getting PortfolioEpic with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetEpics will return all epics
+func (c *Client) GetEpics(filters ...QueryFilter) ([]Epic, error) {
+	return c.GetEpicsCtx(c.context(), filters...)
+}
+
+// GetEpicsCtx is GetEpics with a context that can cancel the requests
+func (c *Client) GetEpicsCtx(ctx context.Context, filters ...QueryFilter) ([]Epic, error) {
+	var ret []Epic
+	it := c.NewIteratorCtx(ctx, "Epics", filters...)
+	for it.Next() {
+		item := Epic{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetEpicByID will return the Epic with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetEpicByID(id int32) (Epic, error) {
+	return c.GetEpicByIDCtx(c.context(), id)
+}
+
+// GetEpicByIDCtx is GetEpicByID with a context that can cancel the request
+func (c *Client) GetEpicByIDCtx(ctx context.Context, id int32) (Epic, error) {
+	ret := Epic{}
+	err := c.GetByIDCtx(ctx, &ret, "Epics", id)
+	if err != nil {
+		return Epic{}, errors.Wrap(err, fmt.Sprintf("error getting Epic with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetEpics will return all epics of the PortfolioEpic that this method is built off of
+func (pe PortfolioEpic) GetEpics(filters ...QueryFilter) ([]Epic, error) {
+	return pe.GetEpicsCtx(pe.client.context(), filters...)
+}
+
+// GetEpicsCtx is GetEpics with a context that can cancel the requests
+func (pe PortfolioEpic) GetEpicsCtx(ctx context.Context, filters ...QueryFilter) ([]Epic, error) {
+	if pe.client == nil {
+		return nil, fmt.Errorf("PortfolioEpic %d has no client, get it from a Client method", pe.ID)
+	}
+	filters = append([]QueryFilter{WhereExpr(Field("PortfolioEpic.Id").Eq(pe.ID))}, filters...)
+	return pe.client.GetEpicsCtx(ctx, filters...)
+}
+
+// GetFeatures will return all features of the Epic that this method is built off of
+func (e Epic) GetFeatures(filters ...QueryFilter) ([]Feature, error) {
+	return e.GetFeaturesCtx(e.client.context(), filters...)
+}
+
+// GetFeaturesCtx is GetFeatures with a context that can cancel the requests
+func (e Epic) GetFeaturesCtx(ctx context.Context, filters ...QueryFilter) ([]Feature, error) {
+	if e.client == nil {
+		return nil, fmt.Errorf("Epic %d has no client, get it from a Client method", e.ID)
+	}
+	filters = append([]QueryFilter{WhereExpr(Field("Epic.Id").Eq(e.ID))}, filters...)
+	return e.client.GetFeaturesCtx(ctx, filters...)
+}
+
+// Create takes a PortfolioEpic struct and crafts a POST request to TP and sends it
+// it returns the ID of the PortfolioEpic created as well as a link to the entity
+// on the Target Process frontend
+func (pe PortfolioEpic) Create() (int32, string, error) {
+	return pe.CreateCtx(pe.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the requests
+func (pe PortfolioEpic) CreateCtx(ctx context.Context) (int32, string, error) {
+	client := pe.client
+	resp := &struct {
+		ID int32 `json:"Id"`
+	}{}
+	body, err := json.Marshal(pe)
+	if err != nil {
+		return 0, "", errors.Wrap(err, fmt.Sprintf("error marshaling POST body for PortfolioEpic %s", pe.Name))
+	}
+
+	client.debugLog(fmt.Sprintf("Attempting to POST PortfolioEpic: %+v", pe))
+	err = client.PostCtx(ctx, resp, "PortfolioEpic", nil, body)
+	if err != nil {
+		return 0, "", errors.Wrap(err, fmt.Sprintf("error POSTing PortfolioEpic %s", pe.Name))
+	}
+	client.debugLog("[targetprocess] Successfully POSTed PortfolioEpic")
+	client.debugLog(fmt.Sprintf("[targetprocess] PortfolioEpic created. ID: %d", resp.ID))
+	link := client.EntityURL(resp.ID)
+	return resp.ID, link, nil
+}
+
+// Create takes an Epic struct and crafts a POST request to TP and sends it
+// it returns the ID of the Epic created as well as a link to the entity
+// on the Target Process frontend
+func (e Epic) Create() (int32, string, error) {
+	return e.CreateCtx(e.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the requests
+func (e Epic) CreateCtx(ctx context.Context) (int32, string, error) {
+	client := e.client
+	resp := &struct {
+		ID int32 `json:"Id"`
+	}{}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return 0, "", errors.Wrap(err, fmt.Sprintf("error marshaling POST body for Epic %s", e.Name))
+	}
+
+	client.debugLog(fmt.Sprintf("Attempting to POST Epic: %+v", e))
+	err = client.PostCtx(ctx, resp, "Epic", nil, body)
+	if err != nil {
+		return 0, "", errors.Wrap(err, fmt.Sprintf("error POSTing Epic %s", e.Name))
+	}
+	client.debugLog("[targetprocess] Successfully POSTed Epic")
+	client.debugLog(fmt.Sprintf("[targetprocess] Epic created. ID: %d", resp.ID))
+	link := client.EntityURL(resp.ID)
+	return resp.ID, link, nil
+}
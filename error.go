@@ -15,9 +15,12 @@
 package targetprocess
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+
+	"github.com/pkg/errors"
 )
 
 type notFoundError interface {
@@ -28,35 +31,136 @@ type permissionDeniedError interface {
 	IsPermissionDenied() bool
 }
 
-type httpClientError struct {
-	msg  string
-	code int
+// APIError is returned when Targetprocess responds with a non 2xx status code.
+// The error payload sent by Targetprocess, if any, is parsed into Status, Message, ErrorID and Type.
+// Use errors.As to get to it from an error returned by this package.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Method is the HTTP method of the request
+	Method string
+	// URL is the request URL with any credentials redacted
+	URL string
+
+	// Status is the Targetprocess status name, e.g. BadRequest
+	Status string
+	// Message is the human readable error message from Targetprocess, with any credentials of the client redacted
+	Message string
+	// ErrorID is the Targetprocess id of the error, useful when contacting their support
+	ErrorID string
+	// Type is the Targetprocess exception type
+	Type string
+
+	// Body is the raw response body with any credentials of the client redacted
+	Body string
 }
 
-func makeHTTPClientError(url string, resp *http.Response) error {
+// apiErrorPayload is the JSON Targetprocess sends with an error. Some endpoints nest it in an Error field.
+type apiErrorPayload struct {
+	Status  string
+	Message string
+	ErrorID string `json:"ErrorId"`
+	Type    string
+	Error   *apiErrorPayload
+}
 
+// makeHTTPClientError builds the APIError for a non 2xx response. Targetprocess echoes the request URL in some
+// error payloads, so the body and message are redacted like the URL is.
+func (c *Client) makeHTTPClientError(resp *http.Response) error {
 	body, _ := ioutil.ReadAll(resp.Body)
-	msg := fmt.Sprintf("HTTP request failure on %s:\n%d: %s", url, resp.StatusCode, string(body))
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       c.redactSecrets(string(body)),
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = redactURL(resp.Request.URL)
+	}
 
-	return &httpClientError{
-		msg:  msg,
-		code: resp.StatusCode,
+	payload := apiErrorPayload{}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Error != nil {
+			payload = *payload.Error
+		}
+		e.Status = payload.Status
+		e.Message = c.redactSecrets(payload.Message)
+		e.ErrorID = payload.ErrorID
+		e.Type = payload.Type
 	}
+	return e
 }
 
-func (e *httpClientError) Error() string            { return e.msg }
-func (e *httpClientError) IsNotFound() bool         { return e.code == 404 }
-func (e *httpClientError) IsPermissionDenied() bool { return e.code == 401 }
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if e.ErrorID != "" {
+		msg = fmt.Sprintf("%s (error id: %s)", msg, e.ErrorID)
+	}
+	return fmt.Sprintf("HTTP request failure on %s %s:\n%d: %s", e.Method, e.URL, e.StatusCode, msg)
+}
+
+// IsNotFound is true for a 404 response
+func (e *APIError) IsNotFound() bool { return e.StatusCode == http.StatusNotFound }
+
+// IsPermissionDenied is true for a 401 response
+func (e *APIError) IsPermissionDenied() bool { return e.StatusCode == http.StatusUnauthorized }
+
+// IsForbidden is true for a 403 response
+func (e *APIError) IsForbidden() bool { return e.StatusCode == http.StatusForbidden }
+
+// IsValidationError is true for a 400 response, which Targetprocess returns for invalid input
+func (e *APIError) IsValidationError() bool { return e.StatusCode == http.StatusBadRequest }
 
-// IsNotFound takes an error and returns true if the error is exactly a not-found error.
+// IsConflict is true for a 409 response
+func (e *APIError) IsConflict() bool { return e.StatusCode == http.StatusConflict }
+
+// IsRateLimited is true for a 429 response
+func (e *APIError) IsRateLimited() bool { return e.StatusCode == http.StatusTooManyRequests }
+
+// IsServerError is true for any 5xx response
+func (e *APIError) IsServerError() bool { return e.StatusCode >= 500 && e.StatusCode <= 599 }
+
+// IsNotFound takes an error and returns true if the error is, or wraps, a not-found error.
 func IsNotFound(err error) bool {
-	nf, ok := err.(notFoundError)
-	return ok && nf.IsNotFound()
+	var nf notFoundError
+	return errors.As(err, &nf) && nf.IsNotFound()
 }
 
-// IsPermissionDenied takes an error and returns true if the error is exactly a
+// IsPermissionDenied takes an error and returns true if the error is, or wraps, a
 // permission-denied error.
 func IsPermissionDenied(err error) bool {
-	pd, ok := err.(permissionDeniedError)
-	return ok && pd.IsPermissionDenied()
+	var pd permissionDeniedError
+	return errors.As(err, &pd) && pd.IsPermissionDenied()
+}
+
+// IsForbidden takes an error and returns true if the error is, or wraps, an APIError for a 403 response
+func IsForbidden(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.IsForbidden()
+}
+
+// IsValidationError takes an error and returns true if the error is, or wraps, an APIError for a 400 response
+func IsValidationError(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.IsValidationError()
+}
+
+// IsConflict takes an error and returns true if the error is, or wraps, an APIError for a 409 response
+func IsConflict(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.IsConflict()
+}
+
+// IsRateLimited takes an error and returns true if the error is, or wraps, an APIError for a 429 response
+func IsRateLimited(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.IsRateLimited()
+}
+
+// IsServerError takes an error and returns true if the error is, or wraps, an APIError for a 5xx response
+func IsServerError(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.IsServerError()
 }
@@ -0,0 +1,130 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        string
+		wantMessage string
+		wantErrorID string
+		predicate   func(error) bool
+	}{
+		{
+			name:        "validation error",
+			statusCode:  400,
+			body:        `{"Status":"BadRequest","Message":"Name is required","Type":"Tp.Core.ValidationException","ErrorId":"abc-123"}`,
+			wantMessage: "Name is required",
+			wantErrorID: "abc-123",
+			predicate:   IsValidationError,
+		},
+		{
+			name:        "nested error payload",
+			statusCode:  404,
+			body:        `{"Error":{"Status":"NotFound","Message":"UserStory with Id 5 not found"}}`,
+			wantMessage: "UserStory with Id 5 not found",
+			predicate:   IsNotFound,
+		},
+		{
+			name:       "permission denied",
+			statusCode: 401,
+			body:       `Unauthorized`,
+			predicate:  IsPermissionDenied,
+		},
+		{
+			name:       "forbidden",
+			statusCode: 403,
+			predicate:  IsForbidden,
+		},
+		{
+			name:       "conflict",
+			statusCode: 409,
+			predicate:  IsConflict,
+		},
+		{
+			name:       "rate limited",
+			statusCode: 429,
+			predicate:  IsRateLimited,
+		},
+		{
+			name:       "server error",
+			statusCode: 500,
+			body:       `<html>oops</html>`,
+			predicate:  IsServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			mockClient, teardown := newMockClient(h, "example", "superSecretToken")
+			defer teardown()
+			mockClient.RetryPolicy = nil
+
+			err := mockClient.Get(new(genericResponse), "UserStories", nil)
+			assert.Error(t, err)
+			wrapped := errors.Wrap(err, "error getting user stories")
+			assert.True(t, tt.predicate(wrapped))
+
+			var apiErr *APIError
+			assert.True(t, errors.As(wrapped, &apiErr))
+			assert.Equal(t, tt.statusCode, apiErr.StatusCode)
+			assert.Equal(t, "GET", apiErr.Method)
+			assert.Equal(t, tt.wantMessage, apiErr.Message)
+			assert.Equal(t, tt.wantErrorID, apiErr.ErrorID)
+			assert.Equal(t, tt.body, apiErr.Body)
+			assert.Contains(t, apiErr.URL, "/api/v2/UserStories/")
+			assert.NotContains(t, wrapped.Error(), "superSecretToken")
+		})
+	}
+}
+
+func TestAPIErrorRedacted(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		_, _ = fmt.Fprintf(w, `{"Status":"BadRequest","Message":"Invalid request %s"}`, r.URL.String())
+	})
+	mockClient, teardown := newMockClient(h, "example", "superSecret+Token==")
+	defer teardown()
+	mockClient.RetryPolicy = nil
+
+	err := mockClient.Get(new(genericResponse), "UserStories", nil)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Contains(t, apiErr.Message, "access_token=REDACTED")
+	for _, s := range []string{apiErr.Message, apiErr.Body, err.Error()} {
+		assert.NotContains(t, s, "superSecret")
+	}
+}
+
+func TestErrorPredicatesOnOtherErrors(t *testing.T) {
+	err := errors.New("something else")
+	assert.False(t, IsNotFound(err))
+	assert.False(t, IsPermissionDenied(err))
+	assert.False(t, IsServerError(err))
+	assert.False(t, IsNotFound(nil))
+}
@@ -15,6 +15,7 @@
 package targetprocess
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -33,6 +34,8 @@ type Feature struct {
 	Description      string        `json:",omitempty"`
 	NumericPriority  float32       `json:",omitempty"`
 	CustomFields     []CustomField `json:",omitempty"`
+	EntityState      *EntityState  `json:",omitempty"`
+	Epic             *Epic         `json:",omitempty"`
 }
 
 // FeatureResponse is a representation of the http response for a group of Features
@@ -45,13 +48,18 @@ type FeatureResponse struct {
 // NewFeature creates a Feature struct with the required fields of
 // name, description, and project.
 func NewFeature(c *Client, name, description, project string) (Feature, error) {
+	return NewFeatureCtx(c.context(), c, name, description, project)
+}
+
+// NewFeatureCtx is NewFeature with a context that can cancel the requests
+func NewFeatureCtx(ctx context.Context, c *Client, name, description, project string) (Feature, error) {
 	f := Feature{
 		client:      c,
 		Name:        name,
 		Description: description,
 	}
 	c.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Project: %s", project))
-	p, err := c.GetProject(project)
+	p, err := c.GetProjectCtx(ctx, project)
 	if err != nil {
 		return Feature{}, err
 	}
@@ -61,34 +69,37 @@ func NewFeature(c *Client, name, description, project string) (Feature, error) {
 
 // GetFeatures will return all features
 func (c *Client) GetFeatures(filters ...QueryFilter) ([]Feature, error) {
-	var ret []Feature
-	out := FeatureResponse{}
+	return c.GetFeaturesCtx(c.context(), filters...)
+}
 
-	err := c.Get(&out, "Feature", nil, filters...)
-	if err != nil {
-		return nil, err
-	}
-	ret = append(ret, out.Items...)
-	for out.Next != "" {
-		innerOut := FeatureResponse{}
-		err := c.GetNext(&innerOut, out.Next)
-		if err != nil {
+// GetFeaturesCtx is GetFeatures with a context that can cancel the requests
+func (c *Client) GetFeaturesCtx(ctx context.Context, filters ...QueryFilter) ([]Feature, error) {
+	var ret []Feature
+	it := c.NewIteratorCtx(ctx, "Feature", filters...)
+	for it.Next() {
+		item := Feature{}
+		if err := it.Decode(&item); err != nil {
 			return ret, err
 		}
-		ret = append(ret, innerOut.Items...)
-		out = innerOut
+		item.client = c
+		ret = append(ret, item)
 	}
-	return ret, nil
+	return ret, it.Err()
 }
 
 // GetFeature will return a single feature based on its name. If somehow there are features with the same name,
 // this will only return the first one.
 func (c *Client) GetFeature(name string) (Feature, error) {
+	return c.GetFeatureCtx(c.context(), name)
+}
+
+// GetFeatureCtx is GetFeature with a context that can cancel the requests
+func (c *Client) GetFeatureCtx(ctx context.Context, name string) (Feature, error) {
 	c.debugLog(fmt.Sprintf("[targetprocess] attempting to get feature: %s", name))
 	ret := Feature{}
 	out := FeatureResponse{}
-	err := c.Get(&out, "Feature", nil,
-		Where(fmt.Sprintf("Name == '%s'", name)),
+	err := c.GetCtx(ctx, &out, "Feature", nil,
+		WhereExpr(Field("Name").Eq(name)),
 		First(),
 	)
 	if err != nil {
@@ -102,11 +113,33 @@ func (c *Client) GetFeature(name string) (Feature, error) {
 	return ret, nil
 }
 
+// GetFeatureByID will return the Feature with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetFeatureByID(id int32) (Feature, error) {
+	return c.GetFeatureByIDCtx(c.context(), id)
+}
+
+// GetFeatureByIDCtx is GetFeatureByID with a context that can cancel the request
+func (c *Client) GetFeatureByIDCtx(ctx context.Context, id int32) (Feature, error) {
+	ret := Feature{}
+	err := c.GetByIDCtx(ctx, &ret, "Features", id)
+	if err != nil {
+		return Feature{}, errors.Wrap(err, fmt.Sprintf("error getting Feature with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
 // NewUserStory will make a UserStory with the Feature that this method is built off of
 func (f Feature) NewUserStory(name, description, project string) (UserStory, error) {
-	us, err := NewUserStory(f.client, name, description, project)
+	return f.NewUserStoryCtx(f.client.context(), name, description, project)
+}
+
+// NewUserStoryCtx is NewUserStory with a context that can cancel the requests
+func (f Feature) NewUserStoryCtx(ctx context.Context, name, description, project string) (UserStory, error) {
+	us, err := NewUserStoryCtx(ctx, f.client, name, description, project)
 	if err != nil {
-		return UserStory{}, nil
+		return UserStory{}, err
 	}
 	us.Feature = &f
 	return us, nil
@@ -116,6 +149,11 @@ func (f Feature) NewUserStory(name, description, project string) (UserStory, err
 // it returns the ID of the Feature created as well as a link to the entity
 // on the Target Process frontend
 func (f Feature) Create() (int32, string, error) {
+	return f.CreateCtx(f.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the requests
+func (f Feature) CreateCtx(ctx context.Context) (int32, string, error) {
 	client := f.client
 	resp := &struct {
 		ID int32 `json:"Id"`
@@ -126,12 +164,52 @@ func (f Feature) Create() (int32, string, error) {
 	}
 
 	client.debugLog(fmt.Sprintf("Attempting to POST Feature: %+v", f))
-	err = client.Post(resp, "Feature", nil, body)
+	err = client.PostCtx(ctx, resp, "Feature", nil, body)
 	if err != nil {
 		return 0, "", errors.Wrap(err, fmt.Sprintf("error POSTing Feature %s", f.Name))
 	}
 	client.debugLog("[targetprocess] Successfully POSTed Feature")
 	client.debugLog(fmt.Sprintf("[targetprocess] Feature created. ID: %d", resp.ID))
-	link := GenerateURL(client.account, resp.ID)
+	link := client.EntityURL(resp.ID)
 	return resp.ID, link, nil
 }
+
+// Update sends only the given fields of the Feature to Targetprocess, leaving every other field untouched,
+// and returns the Feature as it is after the update.
+// Fields are named as in the JSON, ex. f.Update("Name", "Effort")
+func (f Feature) Update(fields ...string) (Feature, error) {
+	return f.UpdateCtx(f.client.context(), fields...)
+}
+
+// UpdateCtx is Update with a context that can cancel the request
+func (f Feature) UpdateCtx(ctx context.Context, fields ...string) (Feature, error) {
+	client := f.client
+	if client == nil {
+		return Feature{}, fmt.Errorf("Feature %d has no client, get it from a Client method", f.ID)
+	}
+	body, err := updateBody(f, f.ID, fields)
+	if err != nil {
+		return Feature{}, errors.Wrap(err, fmt.Sprintf("error building update for Feature %d", f.ID))
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Attempting to update Feature %d: %s", f.ID, body))
+	ret := Feature{}
+	err = client.UpdateCtx(ctx, &ret, "Features", f.ID, body)
+	if err != nil {
+		return Feature{}, errors.Wrap(err, fmt.Sprintf("error updating Feature %d", f.ID))
+	}
+	ret.client = client
+	return ret, nil
+}
+
+// Delete will delete the Feature in Targetprocess
+func (f Feature) Delete() error {
+	return f.DeleteCtx(f.client.context())
+}
+
+// DeleteCtx is Delete with a context that can cancel the request
+func (f Feature) DeleteCtx(ctx context.Context) error {
+	if f.client == nil {
+		return fmt.Errorf("Feature %d has no client, get it from a Client method", f.ID)
+	}
+	return f.client.DeleteCtx(ctx, "Features", f.ID)
+}
@@ -18,12 +18,104 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // QueryFilter accepts a query and returns a query, modifying
-// it in some way before sending
+// it in some way before sending.
+//
+// Filters that depend on the version of the API, like OrderBy or ForAPI, are only applied when
+// the Client runs them. A custom filter that calls one must return what it returns.
 type QueryFilter func(r url.Values) (url.Values, error)
 
+// APIVersion is a version of the Targetprocess REST API
+type APIVersion int
+
+const (
+	// APIv1 is the api/v1 API, used by GetByID and to create, update and delete entities
+	APIv1 APIVersion = 1
+	// APIv2 is the read only api/v2 API, used by Get and the helpers that list entities
+	APIv2 APIVersion = 2
+)
+
+// versionedFilter is what a filter that depends on the API version returns as its error. applyFilters
+// recognizes it and applies the filter with the version of the request.
+type versionedFilter struct {
+	apply func(version APIVersion, values url.Values) (url.Values, error)
+}
+
+func (f *versionedFilter) Error() string {
+	return "query filter depends on the API version and can only be run by a Client"
+}
+
+// versioned makes a QueryFilter out of apply, which is given the version of the API the request is for
+func versioned(apply func(version APIVersion, values url.Values) (url.Values, error)) QueryFilter {
+	f := &versionedFilter{apply: apply}
+	return func(values url.Values) (url.Values, error) {
+		return values, f
+	}
+}
+
+// String returns the version as it appears in the API path, ex. v1
+func (v APIVersion) String() string {
+	return fmt.Sprintf("v%d", int(v))
+}
+
+// versionParams are the query parameters only one version of the API understands
+var versionParams = map[string]APIVersion{
+	"select":  APIv2,
+	"result":  APIv2,
+	"include": APIv1,
+	"append":  APIv1,
+}
+
+// applyFilters runs filters in order on the query of a request to the given version of the API,
+// and refuses a query with parameters the version doesn't understand
+func applyFilters(version APIVersion, values url.Values, filters []QueryFilter) (url.Values, error) {
+	if values == nil {
+		values = url.Values{}
+	}
+	for _, filter := range filters {
+		next, err := applyFilter(version, values, filter)
+		if err != nil {
+			return values, err
+		}
+		if next == nil {
+			next = url.Values{}
+		}
+		values = next
+	}
+	for key, only := range versionParams {
+		if _, ok := values[key]; ok && only != version {
+			return values, fmt.Errorf("query parameter %s is only valid for the %s API, not %s", key, only, version)
+		}
+	}
+	return values, nil
+}
+
+// applyFilter runs a single filter, giving it the API version if it depends on it
+func applyFilter(version APIVersion, values url.Values, filter QueryFilter) (url.Values, error) {
+	next, err := filter(values)
+	var vf *versionedFilter
+	if errors.As(err, &vf) {
+		return vf.apply(version, values)
+	}
+	return next, err
+}
+
+// ForAPI declares that filter is only valid for the given version of the API. The client
+// refuses to send a request to the other version with it.
+func ForAPI(version APIVersion, filter QueryFilter) QueryFilter {
+	return versioned(func(v APIVersion, values url.Values) (url.Values, error) {
+		if v != version {
+			return values, fmt.Errorf("query filter is only valid for the %s API, not %s", version, v)
+		}
+		return applyFilter(v, values, filter)
+	})
+}
+
 // First is a QueryFilter that will only return a single
 // Entity. It returns the first one encountered.
 //
@@ -49,13 +141,23 @@ func MaxPerPage(count int) QueryFilter {
 	}
 }
 
+// Skip is a QueryFilter that skips the first count results. Along with MaxPerPage it can be used
+// to fetch a specific page.
+func Skip(count int) QueryFilter {
+	return func(values url.Values) (url.Values, error) {
+		values.Set("skip", strconv.Itoa(count))
+		return values, nil
+	}
+}
+
 // Result is a QueryFilter that represents the `result` parameter
 // in a url query. It is used to do custom calculations over the
 // entire result set, such as getting the average Effort value
-// across multiple items.
+// across multiple items. It is only valid for the v2 API.
 //
 // It is important to note that this changes the output json and
-// therefore you will need to adjust your receiving struct
+// therefore you will need to adjust your receiving struct, or use
+// Client.Aggregate and Client.AggregateBy which handle it for you
 func Result(query string) QueryFilter {
 	return func(values url.Values) (url.Values, error) {
 		values.Set("result", fmt.Sprintf("{%s}", query))
@@ -66,7 +168,8 @@ func Result(query string) QueryFilter {
 // Select is a QueryFilter that represents the `select` parameter
 // in a url query. It is used to determine what fields are returned.
 // It is important that the struct you are casting your results into
-// accept the fields you specify.
+// accept the fields you specify, SelectStruct derives the query from such a struct.
+// It is only valid for the v2 API, use Include for v1.
 func Select(query string) QueryFilter {
 	return func(values url.Values) (url.Values, error) {
 		values.Set("select", fmt.Sprintf("{%s}", query))
@@ -74,6 +177,54 @@ func Select(query string) QueryFilter {
 	}
 }
 
+// Include is a QueryFilter that represents the `include` parameter
+// in a url query. It limits the response to the given fields.
+// It is only valid for the v1 API, use Select for v2.
+func Include(fields ...string) QueryFilter {
+	return func(values url.Values) (url.Values, error) {
+		values.Set("include", fmt.Sprintf("[%s]", strings.Join(fields, ",")))
+		return values, nil
+	}
+}
+
+// Append is a QueryFilter that represents the `append` parameter
+// in a url query. It adds calculated fields to the response, ex. Append("Tasks-Count").
+// It is only valid for the v1 API.
+func Append(fields ...string) QueryFilter {
+	return func(values url.Values) (url.Values, error) {
+		values.Set("append", fmt.Sprintf("[%s]", strings.Join(fields, ",")))
+		return values, nil
+	}
+}
+
+// OrderBy is a QueryFilter that sorts the results by field, in descending order when desc is true.
+// On the v2 API it can be used multiple times to sort by multiple fields, the first one used being the
+// main sort key. The v1 API can only sort by a single field.
+func OrderBy(field string, desc bool) QueryFilter {
+	return versioned(func(v APIVersion, values url.Values) (url.Values, error) {
+		if v == APIv1 {
+			if values.Get("orderBy") != "" || values.Get("orderByDesc") != "" {
+				return values, fmt.Errorf("the v1 API can only sort by a single field")
+			}
+			if desc {
+				values.Set("orderByDesc", field)
+			} else {
+				values.Set("orderBy", field)
+			}
+			return values, nil
+		}
+		key := field
+		if desc {
+			key += " desc"
+		}
+		if current := values.Get("orderBy"); current != "" {
+			key = current + "," + key
+		}
+		values.Set("orderBy", key)
+		return values, nil
+	})
+}
+
 // Where is a QueryFilter that represents the `where` parameter
 // in a url query.
 func Where(queries ...string) QueryFilter {
@@ -16,6 +16,7 @@ package targetprocess
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"testing"
@@ -299,3 +300,108 @@ func TestWhere(t *testing.T) {
 		})
 	}
 }
+
+func ExampleOrderBy() {
+	tpClient, err := NewClient("accountName", "superSecretToken")
+	if err != nil {
+		fmt.Println("Error creating tp client:", err)
+		os.Exit(1)
+	}
+	userStories, err := tpClient.GetUserStories(
+		false,
+		OrderBy("NumericPriority", false),
+		OrderBy("CreateDate", true),
+	)
+	if err != nil {
+		fmt.Println("Error getting UserStories:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%+v\n", userStories)
+}
+
+func TestOrderBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		version APIVersion
+		filters []QueryFilter
+		wantErr bool
+		want    url.Values
+	}{
+		{
+			name:    "v2 multiple keys",
+			version: APIv2,
+			filters: []QueryFilter{OrderBy("NumericPriority", false), OrderBy("CreateDate", true)},
+			want:    url.Values{"orderBy": []string{"NumericPriority,CreateDate desc"}},
+		},
+		{
+			name:    "v1 ascending",
+			version: APIv1,
+			filters: []QueryFilter{OrderBy("Name", false)},
+			want:    url.Values{"orderBy": []string{"Name"}},
+		},
+		{
+			name:    "v1 descending",
+			version: APIv1,
+			filters: []QueryFilter{OrderBy("CreateDate", true)},
+			want:    url.Values{"orderByDesc": []string{"CreateDate"}},
+		},
+		{
+			name:    "v1 multiple keys",
+			version: APIv1,
+			filters: []QueryFilter{OrderBy("Name", false), OrderBy("CreateDate", true)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vals, err := applyFilters(tt.version, nil, tt.filters)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			for _, key := range []string{"orderBy", "orderByDesc"} {
+				match, w, g := matchedURLValues(tt.want, vals, key)
+				assert.True(t, match)
+				assert.EqualValues(t, w, g)
+			}
+		})
+	}
+}
+
+func TestFilterAPIVersion(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/UserStories/42":
+			assert.Equal(t, "[Name,Tasks]", r.URL.Query().Get("include"))
+			assert.Equal(t, "[Tasks-Count]", r.URL.Query().Get("append"))
+			_, _ = w.Write([]byte(`{"Id": 42}`))
+		case "/api/v2/UserStories/":
+			assert.Equal(t, "10", r.URL.Query().Get("skip"))
+			_, _ = w.Write([]byte(`{"items": []}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	out := UserStory{}
+	assert.NoError(t, mockClient.GetByID(&out, "UserStories", 42, Include("Name", "Tasks"), Append("Tasks-Count")))
+	assert.Error(t, mockClient.GetByID(&out, "UserStories", 42, Select("name")))
+
+	_, err := mockClient.GetUserStories(false, Skip(10))
+	assert.NoError(t, err)
+	_, err = mockClient.GetUserStories(false, Include("Name"))
+	assert.Error(t, err)
+	_, err = mockClient.GetUserStories(false, ForAPI(APIv1, Where("Id == 1")))
+	assert.Error(t, err)
+
+	// a filter building new values doesn't hide the version from the filters after it
+	fresh := func(values url.Values) (url.Values, error) {
+		return url.Values{"skip": []string{"10"}}, nil
+	}
+	_, err = mockClient.GetUserStories(false, fresh, Include("Name"))
+	assert.Error(t, err)
+	assert.NoError(t, mockClient.GetByID(&out, "UserStories", 42, fresh, Include("Name", "Tasks"), Append("Tasks-Count")))
+}
@@ -18,8 +18,12 @@ import (
 	"fmt"
 )
 
-// DateTime currently does nothing special but will represent the TP DateTime objects
-type DateTime string
+// General is a reference to any entity, ex. the UserStory or Bug a comment was left on
+type General struct {
+	ID           int32  `json:"Id,omitempty"`
+	Name         string `json:",omitempty"`
+	ResourceType string `json:",omitempty"`
+}
 
 // Assignments is a generic entity that lists assignments
 type Assignments struct {
@@ -45,13 +49,14 @@ type UserAssignment struct {
 
 // TeamAssignment has it's own unique Id and also includes a reference to the team, which also has an Id
 type TeamAssignment struct {
-	ID        int32    `json:"Id,omitempty"`
-	StartDate DateTime `json:",omitempty"`
-	EndDate   DateTime `json:",omitempty"`
-	Team      *Team    `json:",omitempty"`
+	ID        int32     `json:"Id,omitempty"`
+	StartDate *DateTime `json:",omitempty"`
+	EndDate   *DateTime `json:",omitempty"`
+	Team      *Team     `json:",omitempty"`
 }
 
-// GenerateURL takes an account name and entityID and returns a URL that should work in a browser
+// GenerateURL takes an account name and entityID and returns a URL that should work in a browser.
+// For instances not hosted on tpondemand.com use Client.EntityURL instead.
 func GenerateURL(account string, entityID int32) string {
 	return fmt.Sprintf("https://%s.tpondemand.com/entity/%d/RestUI/board.aspx", account, entityID)
 }
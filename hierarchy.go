@@ -0,0 +1,158 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// hierarchyPageSize is the page size used for each level of the hierarchy
+const hierarchyPageSize = 1000
+
+// PortfolioEpicNode is a PortfolioEpic along with its Epics
+type PortfolioEpicNode struct {
+	PortfolioEpic
+	Epics []*EpicNode
+}
+
+// EpicNode is an Epic along with its Features
+type EpicNode struct {
+	Epic
+	Features []*FeatureNode
+}
+
+// FeatureNode is a Feature along with its UserStories
+type FeatureNode struct {
+	Feature
+	UserStories []*UserStoryNode
+}
+
+// UserStoryNode is a UserStory along with its Tasks
+type UserStoryNode struct {
+	UserStory
+	Tasks []Task
+}
+
+// Hierarchy is the PortfolioEpic -> Epic -> Feature -> UserStory -> Task tree of a project.
+// Entities without a parent are kept at the top level of the tree in the field for their type.
+type Hierarchy struct {
+	PortfolioEpics []*PortfolioEpicNode
+	Epics          []*EpicNode
+	Features       []*FeatureNode
+	UserStories    []*UserStoryNode
+}
+
+// GetHierarchy will load the full hierarchy of the Project with the given ID.
+//
+// Each level is loaded with a single list query for the whole project, so the number of requests does not
+// depend on the shape of the tree: five requests, plus one for every additional 1000 entities in a level.
+func (c *Client) GetHierarchy(projectID int32) (Hierarchy, error) {
+	return c.GetHierarchyCtx(c.context(), projectID)
+}
+
+// GetHierarchyCtx is GetHierarchy with a context that can cancel the requests
+func (c *Client) GetHierarchyCtx(ctx context.Context, projectID int32) (Hierarchy, error) {
+	inProject := WhereExpr(Field("Project.Id").Eq(projectID))
+	pageSize := MaxPerPage(hierarchyPageSize)
+	ret := Hierarchy{}
+
+	portfolioEpics, err := c.GetPortfolioEpicsCtx(ctx, inProject, pageSize)
+	if err != nil {
+		return Hierarchy{}, errors.Wrap(err, fmt.Sprintf("error getting PortfolioEpics of project %d", projectID))
+	}
+	portfolioEpicNodes := map[int32]*PortfolioEpicNode{}
+	for _, pe := range portfolioEpics {
+		node := &PortfolioEpicNode{PortfolioEpic: pe}
+		portfolioEpicNodes[pe.ID] = node
+		ret.PortfolioEpics = append(ret.PortfolioEpics, node)
+	}
+
+	epics, err := c.GetEpicsCtx(ctx, inProject, pageSize)
+	if err != nil {
+		return Hierarchy{}, errors.Wrap(err, fmt.Sprintf("error getting Epics of project %d", projectID))
+	}
+	epicNodes := map[int32]*EpicNode{}
+	for _, e := range epics {
+		node := &EpicNode{Epic: e}
+		epicNodes[e.ID] = node
+		if e.PortfolioEpic != nil && portfolioEpicNodes[e.PortfolioEpic.ID] != nil {
+			parent := portfolioEpicNodes[e.PortfolioEpic.ID]
+			parent.Epics = append(parent.Epics, node)
+			continue
+		}
+		ret.Epics = append(ret.Epics, node)
+	}
+
+	features, err := c.GetFeaturesCtx(ctx, inProject, pageSize)
+	if err != nil {
+		return Hierarchy{}, errors.Wrap(err, fmt.Sprintf("error getting Features of project %d", projectID))
+	}
+	featureNodes := map[int32]*FeatureNode{}
+	for _, f := range features {
+		node := &FeatureNode{Feature: f}
+		featureNodes[f.ID] = node
+		if f.Epic != nil && epicNodes[f.Epic.ID] != nil {
+			parent := epicNodes[f.Epic.ID]
+			parent.Features = append(parent.Features, node)
+			continue
+		}
+		ret.Features = append(ret.Features, node)
+	}
+
+	stories, err := c.GetUserStoriesCtx(ctx, true, inProject, pageSize)
+	if err != nil {
+		return Hierarchy{}, errors.Wrap(err, fmt.Sprintf("error getting UserStories of project %d", projectID))
+	}
+	storyNodes := map[int32]*UserStoryNode{}
+	for _, us := range stories {
+		node := &UserStoryNode{UserStory: us}
+		storyNodes[us.ID] = node
+		if us.Feature != nil && featureNodes[us.Feature.ID] != nil {
+			parent := featureNodes[us.Feature.ID]
+			parent.UserStories = append(parent.UserStories, node)
+			continue
+		}
+		ret.UserStories = append(ret.UserStories, node)
+	}
+
+	tasks, err := c.GetTasksCtx(ctx, true, inProject, pageSize)
+	if err != nil {
+		return Hierarchy{}, errors.Wrap(err, fmt.Sprintf("error getting Tasks of project %d", projectID))
+	}
+	for _, t := range tasks {
+		if t.UserStory == nil || storyNodes[t.UserStory.ID] == nil {
+			continue
+		}
+		parent := storyNodes[t.UserStory.ID]
+		parent.Tasks = append(parent.Tasks, t)
+	}
+	return ret, nil
+}
+
+// GetHierarchy will load the full hierarchy of the Project. See Client.GetHierarchy
+func (p Project) GetHierarchy() (Hierarchy, error) {
+	return p.GetHierarchyCtx(p.client.context())
+}
+
+// GetHierarchyCtx is GetHierarchy with a context that can cancel the requests
+func (p Project) GetHierarchyCtx(ctx context.Context) (Hierarchy, error) {
+	if p.client == nil {
+		return Hierarchy{}, fmt.Errorf("Project %d has no client, get it from a Client method", p.ID)
+	}
+	return p.client.GetHierarchyCtx(ctx, p.ID)
+}
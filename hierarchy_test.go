@@ -0,0 +1,65 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHierarchy(t *testing.T) {
+	var requests []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		assert.Equal(t, "Project.Id == 7", r.URL.Query().Get("where"))
+		assert.Equal(t, "1000", r.URL.Query().Get("take"))
+		switch r.URL.Path {
+		case "/api/v2/PortfolioEpics/":
+			_, _ = w.Write([]byte(`{"items": [{"id": 1, "name": "Roadmap"}]}`))
+		case "/api/v2/Epics/":
+			_, _ = w.Write([]byte(`{"items": [{"id": 2, "portfolioEpic": {"id": 1}}, {"id": 3}]}`))
+		case "/api/v2/Feature/":
+			_, _ = w.Write([]byte(`{"items": [{"id": 4, "epic": {"id": 2}}, {"id": 5, "epic": {"id": 3}}]}`))
+		case "/api/v2/UserStories/":
+			_, _ = w.Write([]byte(`{"items": [{"id": 6, "feature": {"id": 4}}, {"id": 7}]}`))
+		case "/api/v2/Tasks/":
+			_, _ = w.Write([]byte(`{"items": [{"id": 8, "userStory": {"id": 6}}, {"id": 9, "userStory": {"id": 6}}, {"id": 10, "userStory": {"id": 7}}]}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	tree, err := Project{client: mockClient, ID: 7}.GetHierarchy()
+	assert.NoError(t, err)
+	assert.Len(t, requests, 5)
+
+	assert.Len(t, tree.PortfolioEpics, 1)
+	assert.Len(t, tree.PortfolioEpics[0].Epics, 1)
+	epic := tree.PortfolioEpics[0].Epics[0]
+	assert.Equal(t, int32(2), epic.ID)
+	assert.Len(t, epic.Features, 1)
+	assert.Len(t, epic.Features[0].UserStories, 1)
+	assert.Len(t, epic.Features[0].UserStories[0].Tasks, 2)
+
+	assert.Len(t, tree.Epics, 1)
+	assert.Equal(t, int32(5), tree.Epics[0].Features[0].ID)
+	assert.Empty(t, tree.Features)
+	assert.Len(t, tree.UserStories, 1)
+	assert.Equal(t, int32(10), tree.UserStories[0].Tasks[0].ID)
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Iterator lazily walks through all results of a query one item at a time, only requesting the
+// next page once every item of the current one has been consumed. Only one page is held in memory,
+// so it can be used to stream through very large result sets. Create it using NewIterator.
+//
+// Example:
+//   it := client.NewIterator("UserStories", Where("EntityState.Name == 'Done'"))
+//   for it.Next() {
+//     us := UserStory{}
+//     if err := it.Decode(&us); err != nil {
+//       return err
+//     }
+//   }
+//   if err := it.Err(); err != nil {
+//     return err
+//   }
+type Iterator struct {
+	client     *Client
+	ctx        context.Context
+	entityType string
+	filters    []QueryFilter
+	maxPages   int
+
+	items   []json.RawMessage
+	index   int
+	next    string
+	pages   int
+	started bool
+	err     error
+}
+
+// iteratorPage is a page of results with the items left raw so they can be decoded one at a time
+type iteratorPage struct {
+	Items []json.RawMessage
+	Next  string
+	Prev  string
+}
+
+// NewIterator returns an Iterator over every entity of entityType matching the filters
+func (c *Client) NewIterator(entityType string, filters ...QueryFilter) *Iterator {
+	return c.NewIteratorCtx(c.context(), entityType, filters...)
+}
+
+// NewIteratorCtx is NewIterator with a context that can cancel the requests
+func (c *Client) NewIteratorCtx(ctx context.Context, entityType string, filters ...QueryFilter) *Iterator {
+	return &Iterator{
+		client:     c,
+		ctx:        ctx,
+		entityType: entityType,
+		filters:    filters,
+		index:      -1,
+	}
+}
+
+// MaxPages limits how many pages the Iterator will request. Zero, the default, means no limit.
+func (it *Iterator) MaxPages(max int) *Iterator {
+	it.maxPages = max
+	return it
+}
+
+// Next advances the Iterator to the next item, fetching the next page when needed.
+// It returns false when there are no more items or an error happened, check Err to tell them apart.
+func (it *Iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.index+1 >= len(it.items) {
+		if it.started && it.next == "" {
+			return false
+		}
+		if it.maxPages > 0 && it.pages >= it.maxPages {
+			return false
+		}
+		if !it.fetch() {
+			return false
+		}
+	}
+	it.index++
+	return true
+}
+
+// Item returns the raw JSON of the current item
+func (it *Iterator) Item() json.RawMessage {
+	if it.index < 0 || it.index >= len(it.items) {
+		return nil
+	}
+	return it.items[it.index]
+}
+
+// Decode unmarshals the current item into v
+func (it *Iterator) Decode(v interface{}) error {
+	item := it.Item()
+	if item == nil {
+		return errors.New("iterator has no current item")
+	}
+	if err := json.Unmarshal(item, v); err != nil {
+		return errors.Wrapf(err, "JSON decode failed on %s item", it.entityType)
+	}
+	return nil
+}
+
+// Err returns the error that stopped the Iterator, if any
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Pages returns how many pages have been requested so far
+func (it *Iterator) Pages() int {
+	return it.pages
+}
+
+// fetch requests the next page and replaces the current one with it
+func (it *Iterator) fetch() bool {
+	page := iteratorPage{}
+	var err error
+	if !it.started {
+		err = it.client.GetCtx(it.ctx, &page, it.entityType, nil, it.filters...)
+	} else {
+		err = it.client.GetNextCtx(it.ctx, &page, it.next)
+	}
+	it.started = true
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.pages++
+	it.items = page.Items
+	it.index = -1
+	it.next = page.Next
+	return true
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedHandler serves totalPages pages of a single UserStory each, linking them together with Next urls.
+// It fails with errorCode on page failOnPage when that is set.
+func pagedHandler(totalPages, failOnPage, errorCode int, requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		page := skip + 1
+		if page == failOnPage {
+			w.WriteHeader(errorCode)
+			return
+		}
+		next := ""
+		if page < totalPages {
+			next = fmt.Sprintf("https://example.tpondemand.com/api/v2/UserStories?take=1&skip=%d", page)
+		}
+		_, _ = fmt.Fprintf(w, `{"items": [{"Id": %d, "Name": "Story %d"}], "next": "%s"}`, page, page, next)
+	}
+}
+
+func ExampleClient_NewIterator() {
+	tpClient, err := NewClient("exampleaccount", "superSecretToken")
+	if err != nil {
+		fmt.Println("Failed to create tp client:", err)
+		os.Exit(1)
+	}
+	it := tpClient.NewIterator("UserStories", MaxPerPage(1000))
+	for it.Next() {
+		us := UserStory{}
+		if err := it.Decode(&us); err != nil {
+			fmt.Println("Failed to decode UserStory:", err)
+			os.Exit(1)
+		}
+		fmt.Println(us.Name)
+	}
+	if err := it.Err(); err != nil {
+		fmt.Println("Failed to get UserStories:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Read %d pages\n", it.Pages())
+}
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		name         string
+		totalPages   int
+		failOnPage   int
+		maxPages     int
+		stopAfter    int
+		wantIDs      []int32
+		wantPages    int
+		wantRequests int32
+		wantErr      bool
+	}{
+		{
+			name:         "all pages",
+			totalPages:   3,
+			wantIDs:      []int32{1, 2, 3},
+			wantPages:    3,
+			wantRequests: 3,
+		},
+		{
+			name:         "max pages",
+			totalPages:   3,
+			maxPages:     2,
+			wantIDs:      []int32{1, 2},
+			wantPages:    2,
+			wantRequests: 2,
+		},
+		{
+			name:         "stop early",
+			totalPages:   5,
+			stopAfter:    2,
+			wantIDs:      []int32{1, 2},
+			wantPages:    2,
+			wantRequests: 2,
+		},
+		{
+			name:         "error on second page",
+			totalPages:   3,
+			failOnPage:   2,
+			wantIDs:      []int32{1},
+			wantPages:    1,
+			wantRequests: 2,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			mockClient, teardown := newMockClient(pagedHandler(tt.totalPages, tt.failOnPage, 400, &requests), "example", "abcd1234")
+			defer teardown()
+
+			var ids []int32
+			it := mockClient.NewIterator("UserStories", MaxPerPage(1)).MaxPages(tt.maxPages)
+			for it.Next() {
+				us := UserStory{}
+				assert.NoError(t, it.Decode(&us))
+				ids = append(ids, us.ID)
+				if tt.stopAfter > 0 && len(ids) == tt.stopAfter {
+					break
+				}
+			}
+			if tt.wantErr {
+				assert.Error(t, it.Err())
+			} else {
+				assert.NoError(t, it.Err())
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantPages, it.Pages())
+			assert.Equal(t, tt.wantRequests, atomic.LoadInt32(&requests))
+		})
+	}
+}
+
+func TestGetUserStoriesPaging(t *testing.T) {
+	var requests int32
+	mockClient, teardown := newMockClient(pagedHandler(3, 0, 0, &requests), "example", "abcd1234")
+	defer teardown()
+
+	stories, err := mockClient.GetUserStories(true, MaxPerPage(1))
+	assert.NoError(t, err)
+	assert.Len(t, stories, 3)
+	assert.Equal(t, "Story 3", stories[2].Name)
+
+	stories, err = mockClient.GetUserStories(false, MaxPerPage(1))
+	assert.NoError(t, err)
+	assert.Len(t, stories, 1)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ClientOption configures a Client created with NewClientWithOptions
+type ClientOption func(c *Client) error
+
+// WithAccount points the client at a Targetprocess hosted account, i.e.
+//   https://<account>.tpondemand.com
+// It cannot be combined with WithBaseURL.
+func WithAccount(account string) ClientOption {
+	return func(c *Client) error {
+		if account == "" {
+			return errors.New("account cannot be empty")
+		}
+		c.account = account
+		return nil
+	}
+}
+
+// WithBaseURL points the client at a self-hosted or custom domain Targetprocess instance,
+// e.g. https://tp.example.com. It cannot be combined with WithAccount.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return errors.Wrapf(err, "invalid base URL: %s", baseURL)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("base URL must include a scheme and host: %s", baseURL)
+		}
+		u.RawQuery = ""
+		u.Fragment = ""
+		c.siteURL = u
+		return nil
+	}
+}
+
+// WithPathPrefix is used when Targetprocess is served from a sub-path of the host,
+// e.g. WithPathPrefix("/targetprocess") for https://example.com/targetprocess/api/v1/
+func WithPathPrefix(prefix string) ClientOption {
+	return func(c *Client) error {
+		c.pathPrefix = prefix
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for communication
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("http client cannot be nil")
+		}
+		c.Client = httpClient
+		return nil
+	}
+}
+
+// WithTransport sets the RoundTripper used to make requests, e.g. for proxies, mTLS or tracing.
+// The HTTP client is copied before the transport is set, so a client passed in with
+// WithHTTPClient is never modified.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) error {
+		if transport == nil {
+			return errors.New("transport cannot be nil")
+		}
+		c.transport = transport
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of each request attempt. Zero disables the timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.Timeout = timeout
+		return nil
+	}
+}
+
+// WithToken sets the user access token used to authenticate. It is sent in the query string,
+// use WithAuthenticator(HeaderTokenAuth{Token: token}) to send it in a header instead.
+func WithToken(token string) ClientOption {
+	return func(c *Client) error {
+		c.Token = token
+		return nil
+	}
+}
+
+// WithAuthenticator sets how requests are authenticated, e.g. HeaderTokenAuth or BasicAuth
+func WithAuthenticator(auth Authenticator) ClientOption {
+	return func(c *Client) error {
+		if auth == nil {
+			return errors.New("authenticator cannot be nil")
+		}
+		c.Authenticator = auth
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy of the client. Passing nil disables retries.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.RetryPolicy = policy
+		return nil
+	}
+}
+
+// WithRateLimiter sets the RateLimiter of the client
+func WithRateLimiter(rl *RateLimiter) ClientOption {
+	return func(c *Client) error {
+		c.RateLimiter = rl
+		return nil
+	}
+}
+
+// resolveURLs derives the API and entity link URLs from the account or base URL and path prefix
+func (c *Client) resolveURLs() error {
+	switch {
+	case c.account != "" && c.siteURL != nil:
+		return errors.New("only one of an account or a base URL can be set")
+	case c.account != "":
+		siteURL, err := url.Parse(fmt.Sprintf("https://%s.tpondemand.com/", c.account))
+		if err != nil {
+			return errors.Wrapf(err, "invalid account: %s", c.account)
+		}
+		c.siteURL = siteURL
+	case c.siteURL == nil:
+		return errors.New("an account or a base URL is required")
+	}
+
+	path := strings.TrimSuffix(c.siteURL.Path, "/")
+	if prefix := strings.Trim(c.pathPrefix, "/"); prefix != "" {
+		path += "/" + prefix
+	}
+	c.siteURL.Path = path + "/"
+	c.siteURL.RawPath = ""
+
+	var err error
+	c.baseURL, err = c.siteURL.Parse("api/v1/")
+	if err != nil {
+		return err
+	}
+	c.baseURLReadOnly, err = c.siteURL.Parse("api/v2/")
+	return err
+}
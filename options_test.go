@@ -0,0 +1,114 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []ClientOption
+		wantErr       bool
+		wantV1        string
+		wantV2        string
+		wantEntityURL string
+	}{
+		{
+			name:          "account",
+			opts:          []ClientOption{WithAccount("example")},
+			wantV1:        "https://example.tpondemand.com/api/v1/",
+			wantV2:        "https://example.tpondemand.com/api/v2/",
+			wantEntityURL: "https://example.tpondemand.com/entity/42/RestUI/board.aspx",
+		},
+		{
+			name:          "custom domain",
+			opts:          []ClientOption{WithBaseURL("https://tp.example.com/")},
+			wantV1:        "https://tp.example.com/api/v1/",
+			wantV2:        "https://tp.example.com/api/v2/",
+			wantEntityURL: "https://tp.example.com/entity/42/RestUI/board.aspx",
+		},
+		{
+			name:          "self-hosted with path prefix",
+			opts:          []ClientOption{WithPathPrefix("/targetprocess/"), WithBaseURL("http://tp.internal:8080")},
+			wantV1:        "http://tp.internal:8080/targetprocess/api/v1/",
+			wantV2:        "http://tp.internal:8080/targetprocess/api/v2/",
+			wantEntityURL: "http://tp.internal:8080/targetprocess/entity/42/RestUI/board.aspx",
+		},
+		{
+			name:          "base URL with path",
+			opts:          []ClientOption{WithBaseURL("https://example.com/tp"), WithPathPrefix("prod")},
+			wantV1:        "https://example.com/tp/prod/api/v1/",
+			wantV2:        "https://example.com/tp/prod/api/v2/",
+			wantEntityURL: "https://example.com/tp/prod/entity/42/RestUI/board.aspx",
+		},
+		{
+			name:    "no account or base URL",
+			opts:    []ClientOption{WithToken("abcd")},
+			wantErr: true,
+		},
+		{
+			name:    "account and base URL",
+			opts:    []ClientOption{WithAccount("example"), WithBaseURL("https://tp.example.com")},
+			wantErr: true,
+		},
+		{
+			name:    "base URL without scheme",
+			opts:    []ClientOption{WithBaseURL("tp.example.com")},
+			wantErr: true,
+		},
+		{
+			name:    "nil http client",
+			opts:    []ClientOption{WithAccount("example"), WithHTTPClient(nil)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClientWithOptions(tt.opts...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantV1, client.baseURL.String())
+			assert.Equal(t, tt.wantV2, client.baseURLReadOnly.String())
+			assert.Equal(t, tt.wantEntityURL, client.EntityURL(42))
+		})
+	}
+}
+
+func TestNewClientWithOptionsRequest(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tp.example.com", r.Host)
+		assert.Equal(t, "/targetprocess/api/v2/Users/", r.URL.Path)
+		_, _ = w.Write([]byte(okResponse))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	client, err := NewClientWithOptions(
+		WithBaseURL("https://tp.example.com"),
+		WithPathPrefix("targetprocess"),
+		WithHTTPClient(mockClient.Client),
+		WithToken("abcd1234"),
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, client.Get(new(genericResponse), "Users", nil))
+}
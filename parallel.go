@@ -0,0 +1,133 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultParallelWorkers  = 4
+	defaultParallelPageSize = 100
+)
+
+// GetAllParallel will return every entity of entityType matching the filters, fetching pages concurrently.
+// It first asks the API for the total count, then requests all pages at once using skip and take,
+// spread across a pool of workers goroutines. The raw JSON items are returned in the same order a
+// serial request would return them. A workers or pageSize of 0 or less uses a default.
+//
+// Requests still go through the client's RateLimiter, so a limiter bounds the request rate
+// regardless of the number of workers. If the data changes while pages are being fetched an
+// item can be missed or returned twice, as with any skip/take paging.
+func (c *Client) GetAllParallel(entityType string, workers, pageSize int, filters ...QueryFilter) ([]json.RawMessage, error) {
+	return c.GetAllParallelCtx(c.context(), entityType, workers, pageSize, filters...)
+}
+
+// GetAllParallelCtx is GetAllParallel with a context that can cancel the requests
+func (c *Client) GetAllParallelCtx(ctx context.Context, entityType string, workers, pageSize int, filters ...QueryFilter) ([]json.RawMessage, error) {
+	if workers <= 0 {
+		workers = defaultParallelWorkers
+	}
+	if pageSize <= 0 {
+		pageSize = defaultParallelPageSize
+	}
+	total, err := c.countCtx(ctx, entityType, filters...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error counting %s", entityType)
+	}
+	pageCount := (total + pageSize - 1) / pageSize
+	c.debugLog(fmt.Sprintf("[targetprocess] fetching %d %s in %d pages with %d workers", total, entityType, pageCount, workers))
+	if pageCount == 0 {
+		return nil, nil
+	}
+	if workers > pageCount {
+		workers = pageCount
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]json.RawMessage, pageCount)
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				out := iteratorPage{}
+				pageFilters := append(append([]QueryFilter{}, filters...), MaxPerPage(pageSize), Skip(page*pageSize))
+				if err := c.GetCtx(ctx, &out, entityType, nil, pageFilters...); err != nil {
+					errOnce.Do(func() {
+						firstErr = errors.Wrapf(err, "error getting page %d of %s", page+1, entityType)
+						cancel()
+					})
+					continue
+				}
+				pages[page] = out.Items
+			}
+		}()
+	}
+
+sendJobs:
+	for page := 0; page < pageCount; page++ {
+		select {
+		case jobs <- page:
+		case <-ctx.Done():
+			break sendJobs
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ret := make([]json.RawMessage, 0, total)
+	for _, items := range pages {
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+// countCtx returns how many entities of entityType match the filters. Only the where clause they
+// build is kept, as selecting, sorting and paging don't change the count.
+func (c *Client) countCtx(ctx context.Context, entityType string, filters ...QueryFilter) (int, error) {
+	whereOnly := func(values url.Values) (url.Values, error) {
+		ret := url.Values{}
+		if where, ok := values["where"]; ok {
+			ret["where"] = where
+		}
+		return ret, nil
+	}
+	count, err := c.AggregateCtx(ctx, entityType, Count(), append(append([]QueryFilter{}, filters...), whereOnly)...)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingHandler serves total user stories with ids 1 to total, honoring skip and take
+func countingHandler(t *testing.T, total int, failSkip int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "EntityState.Name == 'Open'", q.Get("where"))
+		if q.Get("result") != "" {
+			assert.Equal(t, "{count:count}", q.Get("result"))
+			_, _ = fmt.Fprintf(w, `{"count": %d}`, total)
+			return
+		}
+		skip, _ := strconv.Atoi(q.Get("skip"))
+		take, _ := strconv.Atoi(q.Get("take"))
+		if failSkip > 0 && skip == failSkip {
+			w.WriteHeader(400)
+			return
+		}
+		var items []string
+		for id := skip + 1; id <= skip+take && id <= total; id++ {
+			items = append(items, fmt.Sprintf(`{"Id": %d}`, id))
+		}
+		_, _ = fmt.Fprintf(w, `{"items": [%s]}`, strings.Join(items, ","))
+	}
+}
+
+func TestGetUserStoriesParallel(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		failSkip int
+		workers  int
+		pageSize int
+		wantErr  bool
+	}{
+		{
+			name:     "uneven last page",
+			total:    23,
+			workers:  3,
+			pageSize: 5,
+		},
+		{
+			name:     "more workers than pages",
+			total:    4,
+			workers:  10,
+			pageSize: 5,
+		},
+		{
+			name:     "nothing found",
+			total:    0,
+			workers:  2,
+			pageSize: 5,
+		},
+		{
+			name:     "page failure",
+			total:    23,
+			failSkip: 10,
+			workers:  3,
+			pageSize: 5,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient, teardown := newMockClient(countingHandler(t, tt.total, tt.failSkip), "example", "abcd1234")
+			defer teardown()
+
+			stories, err := mockClient.GetUserStoriesParallel(tt.workers, tt.pageSize, Where("EntityState.Name == 'Open'"))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, stories, tt.total)
+			for i, us := range stories {
+				assert.Equal(t, int32(i+1), us.ID)
+			}
+		})
+	}
+}
+
+func TestGetAllParallelCount(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("result") != "" {
+			for _, key := range []string{"access_token", "format", "resultFormat"} {
+				q.Del(key)
+			}
+			assert.Equal(t, url.Values{"where": []string{"Id > 0"}, "result": []string{"{count:count}"}}, q)
+			_, _ = w.Write([]byte(`{"count": 2}`))
+			return
+		}
+		assert.Equal(t, "{id}", q.Get("select"))
+		assert.Equal(t, "Name desc", q.Get("orderBy"))
+		_, _ = w.Write([]byte(`{"items": [{"id": 1}, {"id": 2}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	items, err := mockClient.GetAllParallel("UserStories", 2, 5, Where("Id > 0"), Select("id"), OrderBy("Name", true), MaxPerPage(1), Skip(7))
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+}
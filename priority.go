@@ -15,6 +15,7 @@
 package targetprocess
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -39,12 +40,17 @@ type PriorityResponse struct {
 
 // GetPriority will return one Priority object by matching the name as well as the EntityType that it's assigned to (ex. UserStory)
 func (c *Client) GetPriority(name, entityType string) (Priority, error) {
+	return c.GetPriorityCtx(c.context(), name, entityType)
+}
+
+// GetPriorityCtx is GetPriority with a context that can cancel the requests
+func (c *Client) GetPriorityCtx(ctx context.Context, name, entityType string) (Priority, error) {
 	c.debugLog(fmt.Sprintf("[targetprocess] attempting to get Priority: %s, for EntityType: %s", name, entityType))
 	ret := Priority{}
 	out := PriorityResponse{}
-	err := c.Get(&out, "Priority", nil,
-		Where(fmt.Sprintf("Name == '%s'", name)),
-		Where(fmt.Sprintf("EntityType.Name == '%s'", entityType)),
+	err := c.GetCtx(ctx, &out, "Priority", nil,
+		WhereExpr(Field("Name").Eq(name)),
+		WhereExpr(Field("EntityType.Name").Eq(entityType)),
 		First(),
 	)
 	if err != nil {
@@ -61,10 +67,31 @@ func (c *Client) GetPriority(name, entityType string) (Priority, error) {
 // SetPriority assigns a priority to a UserStory by first finding the proper Priority in the TargetProcess API and then
 // assigning it to the UserStory object
 func (us *UserStory) SetPriority(priorityName string) error {
-	priority, err := us.client.GetPriority(priorityName, "UserStory")
+	return us.SetPriorityCtx(us.client.context(), priorityName)
+}
+
+// SetPriorityCtx is SetPriority with a context that can cancel the requests
+func (us *UserStory) SetPriorityCtx(ctx context.Context, priorityName string) error {
+	priority, err := us.client.GetPriorityCtx(ctx, priorityName, "UserStory")
 	if err != nil {
 		return err
 	}
 	us.Priority = &priority
 	return nil
 }
+
+// SetPriority assigns a priority to a Bug by first finding the proper Priority in the TargetProcess API and then
+// assigning it to the Bug object
+func (b *Bug) SetPriority(priorityName string) error {
+	return b.SetPriorityCtx(b.client.context(), priorityName)
+}
+
+// SetPriorityCtx is SetPriority with a context that can cancel the requests
+func (b *Bug) SetPriorityCtx(ctx context.Context, priorityName string) error {
+	priority, err := b.client.GetPriorityCtx(ctx, priorityName, "Bug")
+	if err != nil {
+		return err
+	}
+	b.Priority = &priority
+	return nil
+}
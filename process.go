@@ -14,6 +14,13 @@
 
 package targetprocess
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
 // Process contains metadata for the state of a Process. Collection of Processes
 // form Process for Entity. For example, Bug has four Processs by default: Open, Fixed, Invalid and Done
 type Process struct {
@@ -31,22 +38,35 @@ type ProcessResponse struct {
 
 // GetProcesses will return all Processs
 func (c *Client) GetProcesses(filters ...QueryFilter) ([]Process, error) {
-	var ret []Process
-	out := ProcessResponse{}
+	return c.GetProcessesCtx(c.context(), filters...)
+}
 
-	err := c.Get(&out, "Process", nil, filters...)
-	if err != nil {
-		return nil, err
-	}
-	ret = append(ret, out.Items...)
-	for out.Next != "" {
-		innerOut := ProcessResponse{}
-		err := c.GetNext(&innerOut, out.Next)
-		if err != nil {
+// GetProcessesCtx is GetProcesses with a context that can cancel the requests
+func (c *Client) GetProcessesCtx(ctx context.Context, filters ...QueryFilter) ([]Process, error) {
+	var ret []Process
+	it := c.NewIteratorCtx(ctx, "Process", filters...)
+	for it.Next() {
+		item := Process{}
+		if err := it.Decode(&item); err != nil {
 			return ret, err
 		}
-		ret = append(ret, innerOut.Items...)
-		out = innerOut
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetProcessByID will return the Process with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetProcessByID(id int32) (Process, error) {
+	return c.GetProcessByIDCtx(c.context(), id)
+}
+
+// GetProcessByIDCtx is GetProcessByID with a context that can cancel the request
+func (c *Client) GetProcessByIDCtx(ctx context.Context, id int32) (Process, error) {
+	ret := Process{}
+	err := c.GetByIDCtx(ctx, &ret, "Processes", id)
+	if err != nil {
+		return Process{}, errors.Wrap(err, fmt.Sprintf("error getting Process with id %d", id))
 	}
 	return ret, nil
 }
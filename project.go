@@ -15,6 +15,7 @@
 package targetprocess
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -27,10 +28,10 @@ type Project struct {
 	ID              int32         `json:"Id,omitempty"`
 	Name            string        `json:",omitempty"`
 	Description     string        `json:",omitempty"`
-	StartDate       DateTime      `json:",omitempty"`
-	EndDate         DateTime      `json:",omitempty"`
-	CreateDate      DateTime      `json:",omitempty"`
-	ModifyDate      DateTime      `json:",omitempty"`
+	StartDate       *DateTime     `json:",omitempty"`
+	EndDate         *DateTime     `json:",omitempty"`
+	CreateDate      *DateTime     `json:",omitempty"`
+	ModifyDate      *DateTime     `json:",omitempty"`
 	NumericPriority float64       `json:",omitempty"`
 	CustomFields    []CustomField `json:",omitempty"`
 	Effort          float32       `json:",omitempty"`
@@ -51,33 +52,36 @@ type ProjectResponse struct {
 
 // GetProjects will return all projects
 func (c *Client) GetProjects(filters ...QueryFilter) ([]Project, error) {
-	var ret []Project
-	out := ProjectResponse{}
+	return c.GetProjectsCtx(c.context(), filters...)
+}
 
-	err := c.Get(&out, "Project", nil, filters...)
-	if err != nil {
-		return nil, err
-	}
-	ret = append(ret, out.Items...)
-	for out.Next != "" {
-		innerOut := ProjectResponse{}
-		err := c.GetNext(&innerOut, out.Next)
-		if err != nil {
+// GetProjectsCtx is GetProjects with a context that can cancel the requests
+func (c *Client) GetProjectsCtx(ctx context.Context, filters ...QueryFilter) ([]Project, error) {
+	var ret []Project
+	it := c.NewIteratorCtx(ctx, "Project", filters...)
+	for it.Next() {
+		item := Project{}
+		if err := it.Decode(&item); err != nil {
 			return ret, err
 		}
-		ret = append(ret, innerOut.Items...)
-		out = innerOut
+		item.client = c
+		ret = append(ret, item)
 	}
-	return ret, nil
+	return ret, it.Err()
 }
 
 // GetProject will return a single project based on its name. If somehow there are projects with the same name,
 // this will only return the first one.
 func (c *Client) GetProject(name string) (Project, error) {
+	return c.GetProjectCtx(c.context(), name)
+}
+
+// GetProjectCtx is GetProject with a context that can cancel the requests
+func (c *Client) GetProjectCtx(ctx context.Context, name string) (Project, error) {
 	ret := Project{}
 	out := ProjectResponse{}
-	err := c.Get(&out, "Project", nil,
-		Where(fmt.Sprintf("Name == '%s'", name)),
+	err := c.GetCtx(ctx, &out, "Project", nil,
+		WhereExpr(Field("Name").Eq(name)),
 		First(),
 	)
 	if err != nil {
@@ -91,6 +95,23 @@ func (c *Client) GetProject(name string) (Project, error) {
 	return ret, nil
 }
 
+// GetProjectByID will return the Project with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetProjectByID(id int32) (Project, error) {
+	return c.GetProjectByIDCtx(c.context(), id)
+}
+
+// GetProjectByIDCtx is GetProjectByID with a context that can cancel the request
+func (c *Client) GetProjectByIDCtx(ctx context.Context, id int32) (Project, error) {
+	ret := Project{}
+	err := c.GetByIDCtx(ctx, &ret, "Projects", id)
+	if err != nil {
+		return Project{}, errors.Wrap(err, fmt.Sprintf("error getting Project with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
 // NewFeature will make a Feature assigned to the Project that this method is built off of and for the given Team
 func (p Project) NewFeature(name, description string) (Feature, error) {
 	f := Feature{
@@ -104,13 +125,18 @@ func (p Project) NewFeature(name, description string) (Feature, error) {
 
 // NewUserStory will make a UserStory for assigned to the Project that this method is built off of and for the given Team
 func (p Project) NewUserStory(name, description, team string) (UserStory, error) {
+	return p.NewUserStoryCtx(p.client.context(), name, description, team)
+}
+
+// NewUserStoryCtx is NewUserStory with a context that can cancel the requests
+func (p Project) NewUserStoryCtx(ctx context.Context, name, description, team string) (UserStory, error) {
 	us := UserStory{
 		client:      p.client,
 		Name:        name,
 		Description: description,
 	}
 	p.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Team: %s", team))
-	t, err := p.client.GetTeam(team)
+	t, err := p.client.GetTeamCtx(ctx, team)
 	if err != nil {
 		return UserStory{}, err
 	}
@@ -119,10 +145,55 @@ func (p Project) NewUserStory(name, description, team string) (UserStory, error)
 	return us, nil
 }
 
+// Update sends only the given fields of the Project to Targetprocess, leaving every other field untouched,
+// and returns the Project as it is after the update.
+// Fields are named as in the JSON, ex. p.Update("Description", "IsActive")
+func (p Project) Update(fields ...string) (Project, error) {
+	return p.UpdateCtx(p.client.context(), fields...)
+}
+
+// UpdateCtx is Update with a context that can cancel the request
+func (p Project) UpdateCtx(ctx context.Context, fields ...string) (Project, error) {
+	client := p.client
+	if client == nil {
+		return Project{}, fmt.Errorf("Project %d has no client, get it from a Client method", p.ID)
+	}
+	body, err := updateBody(p, p.ID, fields)
+	if err != nil {
+		return Project{}, errors.Wrap(err, fmt.Sprintf("error building update for Project %d", p.ID))
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Attempting to update Project %d: %s", p.ID, body))
+	ret := Project{}
+	err = client.UpdateCtx(ctx, &ret, "Projects", p.ID, body)
+	if err != nil {
+		return Project{}, errors.Wrap(err, fmt.Sprintf("error updating Project %d", p.ID))
+	}
+	ret.client = client
+	return ret, nil
+}
+
+// Delete will delete the Project in Targetprocess
+func (p Project) Delete() error {
+	return p.DeleteCtx(p.client.context())
+}
+
+// DeleteCtx is Delete with a context that can cancel the request
+func (p Project) DeleteCtx(ctx context.Context) error {
+	if p.client == nil {
+		return fmt.Errorf("Project %d has no client, get it from a Client method", p.ID)
+	}
+	return p.client.DeleteCtx(ctx, "Projects", p.ID)
+}
+
 // GetProcess returns the process associated with a project
 func (p Project) GetProcess() (*Process, error) {
-	processList, err := p.client.GetProcesses(
-		Where(fmt.Sprintf("Id == %d", p.Process.ID)),
+	return p.GetProcessCtx(p.client.context())
+}
+
+// GetProcessCtx is GetProcess with a context that can cancel the requests
+func (p Project) GetProcessCtx(ctx context.Context) (*Process, error) {
+	processList, err := p.client.GetProcessesCtx(ctx,
+		WhereExpr(Field("Id").Eq(p.Process.ID)),
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,274 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldPath is what a field reference may look like, ex. EntityState.Name
+var fieldPath = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// Expr is a boolean expression of the v2 query language, built from a Field. Literals are escaped
+// when the expression is built, so values from user input can be used safely.
+//
+//	Field("EntityState.Name").Eq("Done").And(Field("Effort").Gt(3))
+type Expr struct {
+	expr string
+	err  error
+}
+
+// FieldRef is a reference to a field, a path through references or an aggregation over a collection.
+// It is created with Field.
+type FieldRef struct {
+	path string
+	err  error
+}
+
+// Collection is a collection of an entity filtered by an expression, ex. the UserStories of a Feature
+// that are not done. It is created with FieldRef.Where.
+type Collection struct {
+	path string
+	err  error
+}
+
+// Field references the field at the given path, ex. Field("Name") or Field("Feature.Epic.Id")
+func Field(path string) FieldRef {
+	if !fieldPath.MatchString(path) {
+		return FieldRef{err: fmt.Errorf("invalid field '%s'", path)}
+	}
+	return FieldRef{path: path}
+}
+
+// String renders the field reference in the v2 query language
+func (f FieldRef) String() string {
+	return f.path
+}
+
+// Eq matches when the field is equal to value
+func (f FieldRef) Eq(value interface{}) Expr {
+	return f.compare("==", value)
+}
+
+// Ne matches when the field is not equal to value
+func (f FieldRef) Ne(value interface{}) Expr {
+	return f.compare("!=", value)
+}
+
+// Gt matches when the field is greater than value
+func (f FieldRef) Gt(value interface{}) Expr {
+	return f.compare(">", value)
+}
+
+// Gte matches when the field is greater than or equal to value
+func (f FieldRef) Gte(value interface{}) Expr {
+	return f.compare(">=", value)
+}
+
+// Lt matches when the field is less than value
+func (f FieldRef) Lt(value interface{}) Expr {
+	return f.compare("<", value)
+}
+
+// Lte matches when the field is less than or equal to value
+func (f FieldRef) Lte(value interface{}) Expr {
+	return f.compare("<=", value)
+}
+
+// IsNull matches when the field is not set
+func (f FieldRef) IsNull() Expr {
+	return f.compare("==", nil)
+}
+
+// IsNotNull matches when the field is set
+func (f FieldRef) IsNotNull() Expr {
+	return f.compare("!=", nil)
+}
+
+// In matches when the field is equal to any of values
+func (f FieldRef) In(values ...interface{}) Expr {
+	if f.err != nil {
+		return Expr{err: f.err}
+	}
+	if len(values) == 0 {
+		return Expr{err: fmt.Errorf("no values to match %s against", f.path)}
+	}
+	literals := make([]string, len(values))
+	for i, value := range values {
+		lit, err := literal(value)
+		if err != nil {
+			return Expr{err: err}
+		}
+		literals[i] = lit
+	}
+	return Expr{expr: fmt.Sprintf("%s in [%s]", f.path, strings.Join(literals, ","))}
+}
+
+// Contains matches when the text field contains s
+func (f FieldRef) Contains(s string) Expr {
+	if f.err != nil {
+		return Expr{err: f.err}
+	}
+	lit, _ := literal(s)
+	return Expr{expr: fmt.Sprintf("%s.Contains(%s)", f.path, lit)}
+}
+
+// Where filters the collection at the field by an expression, ex. Field("UserStories").Where(...)
+func (f FieldRef) Where(e Expr) Collection {
+	if f.err != nil {
+		return Collection{err: f.err}
+	}
+	if e.err != nil {
+		return Collection{err: e.err}
+	}
+	return Collection{path: fmt.Sprintf("%s.Where(%s)", f.path, e.expr)}
+}
+
+func (f FieldRef) compare(op string, value interface{}) Expr {
+	if f.err != nil {
+		return Expr{err: f.err}
+	}
+	lit, err := literal(value)
+	if err != nil {
+		return Expr{err: err}
+	}
+	return Expr{expr: fmt.Sprintf("%s %s %s", f.path, op, lit)}
+}
+
+// String renders the collection in the v2 query language, ex. for use in Select
+func (c Collection) String() string {
+	return c.path
+}
+
+// Count references the number of entities in the collection
+func (c Collection) Count() FieldRef {
+	return FieldRef{path: c.path + ".Count", err: c.err}
+}
+
+// Sum references the sum of the given field over the entities in the collection
+func (c Collection) Sum(field string) FieldRef {
+	f := Field(field)
+	if f.err != nil {
+		return f
+	}
+	return FieldRef{path: fmt.Sprintf("%s.Sum(%s)", c.path, f.path), err: c.err}
+}
+
+// Any matches when the collection has at least one entity
+func (c Collection) Any() Expr {
+	return c.Count().Gt(0)
+}
+
+// And matches when the expression and all of others match
+func (e Expr) And(others ...Expr) Expr {
+	return e.join(" and ", others)
+}
+
+// Or matches when the expression or any of others match
+func (e Expr) Or(others ...Expr) Expr {
+	joined := e.join(" or ", others)
+	if joined.err != nil || len(others) == 0 {
+		return joined
+	}
+	return Expr{expr: "(" + joined.expr + ")"}
+}
+
+func (e Expr) join(op string, others []Expr) Expr {
+	if e.err != nil {
+		return e
+	}
+	parts := []string{e.expr}
+	for _, other := range others {
+		if other.err != nil {
+			return other
+		}
+		parts = append(parts, other.expr)
+	}
+	return Expr{expr: strings.Join(parts, op)}
+}
+
+// String renders the expression in the v2 query language
+func (e Expr) String() string {
+	return e.expr
+}
+
+// Err returns the error found while building the expression, ex. an unsupported literal
+func (e Expr) Err() error {
+	return e.err
+}
+
+// WhereExpr is a QueryFilter that represents the `where` parameter in a url query, built
+// from expressions instead of raw strings. Like Where, all expressions must match.
+// It is only valid for the v2 API.
+func WhereExpr(exprs ...Expr) QueryFilter {
+	return ForAPI(APIv2, func(values url.Values) (url.Values, error) {
+		queries := make([]string, len(exprs))
+		for i, e := range exprs {
+			if e.err != nil {
+				return values, e.err
+			}
+			queries[i] = e.expr
+		}
+		return Where(queries...)(values)
+	})
+}
+
+// literal renders a Go value as a literal of the v2 query language. A quote in a string is
+// escaped by doubling it, so O'Brien is sent as two quotes in a row.
+func literal(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return "'" + v.Format("2006-01-02") + "'", nil
+		}
+		return "'" + v.Format(time.RFC3339) + "'", nil
+	case DateTime:
+		return literal(v.Time())
+	case *DateTime:
+		if v == nil {
+			return "null", nil
+		}
+		return literal(v.Time())
+	case FieldRef:
+		return v.path, v.err
+	}
+	return "", fmt.Errorf("unsupported literal %v of type %T", value, value)
+}
+
+// day truncates t to the start of its day
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
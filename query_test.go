@@ -0,0 +1,118 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpr(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    Expr
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "and",
+			expr: Field("EntityState.Name").Eq("Done").And(Field("Effort").Gt(3)),
+			want: "EntityState.Name == 'Done' and Effort > 3",
+		},
+		{
+			name: "quotes are doubled",
+			expr: Field("Name").Eq("O'Brien"),
+			want: "Name == 'O''Brien'",
+		},
+		{
+			name: "injection stays inside the literal",
+			expr: Field("Name").Eq("x' or Id > 0 or Name == 'y"),
+			want: "Name == 'x'' or Id > 0 or Name == ''y'",
+		},
+		{
+			name: "or inside and",
+			expr: Field("Effort").Lte(2.5).And(Field("Team").IsNull().Or(Field("Team.Id").Ne(int32(3)))),
+			want: "Effort <= 2.5 and (Team == null or Team.Id != 3)",
+		},
+		{
+			name: "in",
+			expr: Field("Id").In(1, 2, 3),
+			want: "Id in [1,2,3]",
+		},
+		{
+			name: "contains",
+			expr: Field("Name").Contains("it's"),
+			want: "Name.Contains('it''s')",
+		},
+		{
+			name: "dates",
+			expr: Field("StartDate").Gte(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)).And(
+				Field("CreateDate").Lt(time.Date(2020, 6, 1, 13, 30, 0, 0, time.UTC))),
+			want: "StartDate >= '2020-06-01' and CreateDate < '2020-06-01T13:30:00Z'",
+		},
+		{
+			name: "nested collection",
+			expr: Field("UserStories").Where(Field("EntityState.IsFinal").Eq(false)).Any(),
+			want: "UserStories.Where(EntityState.IsFinal == false).Count > 0",
+		},
+		{
+			name: "compare fields",
+			expr: Field("TimeSpent").Gt(Field("Effort")),
+			want: "TimeSpent > Effort",
+		},
+		{
+			name:    "invalid field",
+			expr:    Field("Name == 'x' or Id").Eq(1),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported literal",
+			expr:    Field("Id").Eq(struct{}{}),
+			wantErr: true,
+		},
+		{
+			name:    "error in a nested expression",
+			expr:    Field("Id").Eq(1).Or(Field("Id").In()),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := applyFilters(APIv2, nil, []QueryFilter{WhereExpr(tt.expr)})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, values.Get("where"))
+		})
+	}
+}
+
+func TestGetProjectEscapesName(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Name == 'O''Brien'", r.URL.Query().Get("where"))
+		_, _ = w.Write([]byte(`{"items": [{"id": 1, "name": "O'Brien"}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	p, err := mockClient.GetProject("O'Brien")
+	assert.NoError(t, err)
+	assert.Equal(t, "O'Brien", p.Name)
+}
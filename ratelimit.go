@@ -0,0 +1,116 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter that can be shared by any number of goroutines
+// using the same Client. Every request made by the Client waits for a token before it is sent.
+// Create it using NewRateLimiter.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	// rate is how many tokens are added to the bucket every second
+	rate float64
+	// burst is the size of the bucket
+	burst float64
+	// tokens is the number of tokens currently available. It goes negative
+	// when callers have reserved tokens that have not been added yet.
+	tokens float64
+	last   time.Time
+
+	stats RateLimiterStats
+}
+
+// RateLimiterStats holds counters for how a RateLimiter has been used
+type RateLimiterStats struct {
+	// Requests is the number of requests that have passed through the limiter
+	Requests int64
+	// Throttled is the number of requests that had to wait for a token
+	Throttled int64
+	// TimeThrottled is the total time requests spent waiting for a token
+	TimeThrottled time.Duration
+}
+
+// NewRateLimiter returns a RateLimiter allowing requestsPerSecond requests on average,
+// with bursts of up to burst requests. A burst below 1 is treated as 1 and a requestsPerSecond
+// of 0 or less disables limiting.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request is allowed to be made or ctx is done. If ctx is done first
+// the reserved token is given back and the context error is returned.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	wait := rl.reserve()
+	if wait <= 0 {
+		return nil
+	}
+	start := time.Now()
+	err := sleepContext(ctx, wait)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.stats.TimeThrottled += time.Since(start)
+	if err != nil {
+		rl.tokens++
+		rl.stats.Requests--
+		return err
+	}
+	rl.stats.Throttled++
+	return nil
+}
+
+// Stats returns a snapshot of the limiter's counters
+func (rl *RateLimiter) Stats() RateLimiterStats {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.stats
+}
+
+// reserve takes a token from the bucket and returns how long the caller needs to wait for it
+func (rl *RateLimiter) reserve() time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+	rl.tokens--
+	rl.stats.Requests++
+
+	if rl.tokens >= 0 || rl.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+}
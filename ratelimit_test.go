@@ -0,0 +1,75 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	rl := NewRateLimiter(100, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, rl.Wait(context.Background()))
+	}
+	// two requests fit in the burst, the other two need ~10ms each
+	assert.True(t, time.Since(start) >= 15*time.Millisecond)
+
+	stats := rl.Stats()
+	assert.Equal(t, int64(4), stats.Requests)
+	assert.Equal(t, int64(2), stats.Throttled)
+	assert.True(t, stats.TimeThrottled > 0)
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	rl := NewRateLimiter(0.1, 1)
+	assert.NoError(t, rl.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := rl.Wait(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, int64(1), rl.Stats().Requests)
+	assert.Equal(t, int64(0), rl.Stats().Throttled)
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(okResponse))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+	mockClient.RateLimiter = NewRateLimiter(50, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, mockClient.Get(new(genericResponse), "Users", nil))
+		}()
+	}
+	wg.Wait()
+
+	stats := mockClient.RateLimiter.Stats()
+	assert.Equal(t, int64(5), stats.Requests)
+	assert.Equal(t, int64(4), stats.Throttled)
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// postSafeStatusCodes are the status codes where Targetprocess tells us it did not process
+// the request at all, so resending a POST cannot create a duplicate entity.
+var postSafeStatusCodes = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+
+// RetryPolicy controls how the Client retries requests that fail with a transient error.
+// GET requests are retried on any of the RetryableStatusCodes and on network errors.
+// POST requests are only retried when the request provably was not processed: a 429 or 503
+// response, or a network error that happened before a connection was established.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made, including the first one.
+	// A value below 2 disables retries.
+	MaxAttempts int
+
+	// BaseBackoff is the wait before the first retry. It doubles on every following retry.
+	BaseBackoff time.Duration
+
+	// MaxBackoff caps every wait between attempts, including one requested by a Retry-After header.
+	// Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction (0 to 1) of each backoff that is randomized to avoid
+	// many clients retrying in lockstep.
+	Jitter float64
+
+	// RetryableStatusCodes are the HTTP status codes that will cause a GET to be retried
+	RetryableStatusCodes []int
+
+	// RetryNetworkErrors enables retries for requests that failed without a response
+	RetryNetworkErrors bool
+
+	// RespectRetryAfter makes the client wait for the duration given in a Retry-After
+	// response header instead of the computed backoff, when it is present. The wait is
+	// still capped by MaxBackoff.
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used by NewClient
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNetworkErrors: true,
+		RespectRetryAfter:  true,
+	}
+}
+
+// attempts returns the total number of attempts allowed by the policy
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 2 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// shouldRetry decides if a request should be sent again given the outcome of the last attempt
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	idempotent := req.Method != http.MethodPost
+	if err != nil {
+		if !p.RetryNetworkErrors {
+			return false
+		}
+		return idempotent || isDialError(err)
+	}
+	if idempotent {
+		return containsInt(p.RetryableStatusCodes, resp.StatusCode)
+	}
+	return containsInt(p.RetryableStatusCodes, resp.StatusCode) && containsInt(postSafeStatusCodes, resp.StatusCode)
+}
+
+// backoff returns how long to wait before the next attempt. attempt is the number of the attempt that just failed.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if p.RespectRetryAfter && resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+				wait = p.MaxBackoff
+			}
+			return wait
+		}
+	}
+	wait := float64(p.BaseBackoff) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait -= wait * math.Min(p.Jitter, 1) * rand.Float64() // nolint:gosec
+	}
+	return time.Duration(wait)
+}
+
+// parseRetryAfter handles both forms of the Retry-After header: a number of seconds or an HTTP date
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isDialError reports whether err happened while establishing the connection, before anything was sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// sleepContext waits for d or until ctx is done, whichever happens first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func containsInt(list []int, i int) bool {
+	for _, item := range list {
+		if item == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.BaseBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		statusCodes  []int
+		retryAfter   string
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{
+			name:         "GET retried until success",
+			method:       "GET",
+			statusCodes:  []int{503, 502, 200},
+			wantAttempts: 3,
+		},
+		{
+			name:         "GET gives up after max attempts",
+			method:       "GET",
+			statusCodes:  []int{503, 503, 503, 503, 503},
+			wantAttempts: 4,
+			wantErr:      true,
+		},
+		{
+			name:         "GET not retried on 404",
+			method:       "GET",
+			statusCodes:  []int{404, 200},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "GET honors Retry-After",
+			method:       "GET",
+			statusCodes:  []int{429, 200},
+			retryAfter:   "0",
+			wantAttempts: 2,
+		},
+		{
+			name:         "POST retried on 429",
+			method:       "POST",
+			statusCodes:  []int{429, 200},
+			wantAttempts: 2,
+		},
+		{
+			name:         "POST not retried on 502",
+			method:       "POST",
+			statusCodes:  []int{502, 200},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				if r.Method == "POST" {
+					body, _ := ioutil.ReadAll(r.Body)
+					assert.Equal(t, `{"Name":"test"}`, string(body))
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.statusCodes[n-1])
+				_, _ = w.Write([]byte(okResponse))
+			})
+			mockClient, teardown := newMockClient(h, "example", "abcd1234")
+			defer teardown()
+			mockClient.RetryPolicy = testRetryPolicy()
+
+			resp := new(genericResponse)
+			var err error
+			if tt.method == "POST" {
+				err = mockClient.Post(resp, "UserStory", nil, []byte(`{"Name":"test"}`))
+			} else {
+				err = mockClient.Get(resp, "UserStories", nil)
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1, nil))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3, nil))
+	assert.Equal(t, time.Second, p.backoff(10, nil))
+
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		wait := p.backoff(2, nil)
+		assert.True(t, wait >= 100*time.Millisecond && wait <= 200*time.Millisecond, "unexpected backoff %s", wait)
+	}
+
+	p.RespectRetryAfter = true
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	assert.Equal(t, time.Second, p.backoff(1, resp), "Retry-After is capped by MaxBackoff")
+
+	p.MaxBackoff = 0
+	assert.Equal(t, 7*time.Second, p.backoff(1, resp))
+
+	p.MaxBackoff = 10 * time.Second
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, 10*time.Second, p.backoff(1, resp))
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// SelectExpression derives the v2 `select` expression for the struct v, or a pointer or slice of it.
+//
+// Each exported field is selected under its JSON name, or its Go name when it has none. The `tp` tag
+// sets the expression to select instead of the field with the same name, relative to the entity, and
+// a field tagged `tp:"-"` or `json:"-"` is skipped. The fields of an embedded struct are selected as if
+// they were fields of the outer struct, like encoding/json decodes them. Struct fields are projected
+// field by field and slices of structs select from a collection, ex:
+//
+//	type Report struct {
+//		ID      int32  `json:"id"`
+//		Name    string `json:"name"`
+//		Stories int    `json:"stories" tp:"UserStories.Count"`
+//		Project struct {
+//			Name string `json:"name"`
+//		} `json:"project"`
+//		Tasks []struct {
+//			Name string `json:"name"`
+//		} `json:"tasks"`
+//	}
+//
+// selects {id,name,stories:UserStories.Count,project:{name:project.name},tasks:tasks.Select({name})}
+//
+// A struct that refers to itself, like EntityState through ParentEntityState, can't be projected
+// and is an error unless the field is skipped with `tp:"-"`.
+func SelectExpression(v interface{}) (string, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("cannot derive a select expression from %T, it must be a struct", v)
+	}
+	fields, err := projection(t, "", map[reflect.Type]bool{t: true})
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(fields, ","), nil
+}
+
+// SelectStruct is a QueryFilter that selects the fields of the struct v. See SelectExpression.
+func SelectStruct(v interface{}) QueryFilter {
+	expr, err := SelectExpression(v)
+	if err != nil {
+		return func(values url.Values) (url.Values, error) {
+			return values, err
+		}
+	}
+	return Select(expr)
+}
+
+// projection returns the select items for the fields of t, with paths relative to prefix.
+// visiting holds the struct types on the path to t, so a type that refers to itself is caught.
+func projection(t reflect.Type, prefix string, visiting map[reflect.Type]bool) ([]string, error) {
+	var (
+		ret      []string
+		embedded []string
+		aliases  = map[string]bool{}
+	)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		alias := field.Name
+		named := false
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if name := strings.Split(tag, ",")[0]; name != "" {
+				alias = name
+				named = true
+			}
+		}
+		if field.Anonymous && !named {
+			// encoding/json promotes the fields of an embedded struct, even one of an unexported type
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isLeaf(ft) && (field.PkgPath == "" || field.Type.Kind() != reflect.Ptr) {
+				if field.Tag.Get("tp") == "-" {
+					continue
+				}
+				fields, err := nestedProjection(field, ft, prefix, visiting)
+				if err != nil {
+					return nil, err
+				}
+				embedded = append(embedded, fields...)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		aliases[alias] = true
+		path := alias
+		if expr, ok := field.Tag.Lookup("tp"); ok {
+			if expr == "-" {
+				continue
+			}
+			path = expr
+		}
+		path = prefix + path
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch {
+		case isLeaf(ft):
+			if path == alias {
+				ret = append(ret, alias)
+				continue
+			}
+			ret = append(ret, alias+":"+path)
+		case ft.Kind() == reflect.Struct:
+			fields, err := nestedProjection(field, ft, path+".", visiting)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, fmt.Sprintf("%s:{%s}", alias, strings.Join(fields, ",")))
+		default:
+			elem := ft.Elem()
+			for elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			if isLeaf(elem) || elem.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("cannot select field %s of type %s, use a tp tag with an expression", field.Name, field.Type)
+			}
+			fields, err := nestedProjection(field, elem, "", visiting)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, fmt.Sprintf("%s:%s.Select({%s})", alias, path, strings.Join(fields, ",")))
+		}
+	}
+	// like in encoding/json, a field of the outer struct hides an embedded one with the same name
+	for _, item := range embedded {
+		alias := strings.SplitN(item, ":", 2)[0]
+		if !aliases[alias] {
+			aliases[alias] = true
+			ret = append(ret, item)
+		}
+	}
+	return ret, nil
+}
+
+// nestedProjection returns the projection of the struct type t of field, failing when t is already
+// being projected, as it would be selected endlessly
+func nestedProjection(field reflect.StructField, t reflect.Type, prefix string, visiting map[reflect.Type]bool) ([]string, error) {
+	if visiting[t] {
+		return nil, fmt.Errorf("cannot select field %s, %s refers to itself, skip it with a tp:\"-\" tag or use a field type that doesn't", field.Name, t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	return projection(t, prefix, visiting)
+}
+
+// isLeaf tells if values of type t are selected whole rather than field by field
+func isLeaf(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return false
+	case reflect.Slice, reflect.Array:
+		return t.Elem().Kind() != reflect.Struct && t.Elem().Kind() != reflect.Ptr
+	}
+	return true
+}
+
+// GetSelected fills out, a pointer to a slice of structs, with every entity of entityType matching the
+// filters. Only the fields of the struct are selected, see SelectExpression, and every page of results
+// is requested.
+//
+// An error is returned when the response holds a field the struct has no place for, which usually means
+// a tag doesn't match the name the API gave to a field.
+func (c *Client) GetSelected(out interface{}, entityType string, filters ...QueryFilter) error {
+	return c.GetSelectedCtx(c.context(), out, entityType, filters...)
+}
+
+// GetSelectedCtx is GetSelected with a context that can cancel the requests
+func (c *Client) GetSelectedCtx(ctx context.Context, out interface{}, entityType string, filters ...QueryFilter) error {
+	ptr := reflect.ValueOf(out)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("out must be a pointer to a slice of structs, not %T", out)
+	}
+	slice := ptr.Elem()
+	expr, err := SelectExpression(out)
+	if err != nil {
+		return err
+	}
+
+	it := c.NewIteratorCtx(ctx, entityType, append(append([]QueryFilter{}, filters...), Select(expr))...)
+	for it.Next() {
+		item := reflect.New(slice.Type().Elem())
+		dec := json.NewDecoder(bytes.NewReader(it.Item()))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(item.Interface()); err != nil {
+			return errors.Wrapf(err, "JSON decode failed on %s item, does %s match select {%s}?", entityType, slice.Type().Elem(), expr)
+		}
+		slice.Set(reflect.Append(slice, item.Elem()))
+	}
+	return it.Err()
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storyReport struct {
+	ID       int32  `json:"id"`
+	Name     string `json:"name"`
+	Done     int    `json:"done" tp:"Tasks.Where(EntityState.IsFinal == true).Count"`
+	internal string
+	Skipped  string `tp:"-"`
+	Project  *struct {
+		ID      int32 `json:"id"`
+		Process struct {
+			Name string `json:"name"`
+		} `json:"process"`
+	} `json:"project"`
+	Tasks []struct {
+		Name  string  `json:"name"`
+		Spent float32 `json:"spent" tp:"TimeSpent"`
+	} `json:"tasks"`
+	Tags []string `json:"tags"`
+}
+
+type selectBase struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+type selectNode struct {
+	ID     int32       `json:"id"`
+	Parent *selectNode `json:"parent"`
+}
+
+func TestSelectExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "report",
+			v:    &[]storyReport{},
+			want: "id,name,done:Tasks.Where(EntityState.IsFinal == true).Count," +
+				"project:{id:project.id,process:{name:project.process.name}}," +
+				"tasks:tasks.Select({name,spent:TimeSpent}),tags",
+		},
+		{
+			name: "go names",
+			v: struct {
+				Name   string
+				Effort float32 `json:",omitempty"`
+			}{},
+			want: "Name,Effort",
+		},
+		{
+			name: "embedded struct",
+			v: struct {
+				selectBase
+				Name   string  `json:"name" tp:"Description"`
+				Effort float32 `json:"effort"`
+			}{},
+			want: "name:Description,effort,id",
+		},
+		{
+			name: "embedded pointers in a nested struct",
+			v: struct {
+				Feature struct {
+					*EntityType
+					*selectBase
+				} `json:"feature"`
+			}{},
+			want: "feature:{Id:feature.Id,Name:feature.Name}",
+		},
+		{
+			name:    "self reference",
+			v:       selectNode{},
+			wantErr: true,
+		},
+		{
+			name:    "self reference in a collection",
+			v:       struct{ Children []selectNode }{},
+			wantErr: true,
+		},
+		{
+			name: "self reference through an embedded struct",
+			v: struct {
+				EntityState
+			}{},
+			wantErr: true,
+		},
+		{
+			name: "self reference skipped",
+			v: struct {
+				ID     int32       `json:"id"`
+				Parent *selectNode `json:"parent" tp:"-"`
+				State  string      `json:"state" tp:"EntityState.Name"`
+			}{},
+			want: "id,state:EntityState.Name",
+		},
+		{
+			name:    "not a struct",
+			v:       []string{},
+			wantErr: true,
+		},
+		{
+			name: "slice of pointers to values",
+			v: struct {
+				Efforts []*float32
+			}{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectExpression(tt.v)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetSelected(t *testing.T) {
+	type report struct {
+		ID      int32 `json:"id"`
+		Stories int   `json:"stories" tp:"UserStories.Count"`
+	}
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Feature/", r.URL.Path)
+		assert.Equal(t, "{id,stories:UserStories.Count}", r.URL.Query().Get("select"))
+		switch r.URL.Query().Get("where") {
+		case "Id == 1":
+			_, _ = w.Write([]byte(`{"items": [{"id": 1, "stories": 4}], "next": "https://example.tpondemand.com/api/v2/Feature/?where=Id%20%3D%3D%202&select=%7Bid,stories:UserStories.Count%7D"}`))
+		case "Id == 2":
+			_, _ = w.Write([]byte(`{"items": [{"id": 2, "stories": 0}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"items": [{"id": 3, "stories": 1, "name": "unexpected"}]}`))
+		}
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	var reports []report
+	assert.NoError(t, mockClient.GetSelected(&reports, "Feature", Where("Id == 1")))
+	assert.Equal(t, []report{{ID: 1, Stories: 4}, {ID: 2}}, reports)
+
+	var unexpected []report
+	assert.Error(t, mockClient.GetSelected(&unexpected, "Feature"))
+
+	type embedded struct {
+		report
+	}
+	var reportsEmbedded []embedded
+	assert.NoError(t, mockClient.GetSelected(&reportsEmbedded, "Feature", Where("Id == 1")))
+	assert.Equal(t, []embedded{{report{ID: 1, Stories: 4}}, {report{ID: 2}}}, reportsEmbedded)
+
+	assert.Error(t, mockClient.GetSelected(reports, "Feature"))
+}
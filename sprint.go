@@ -0,0 +1,440 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Release matches up with a targetprocess Release
+type Release struct {
+	client *Client
+
+	ID           int32         `json:"Id,omitempty"`
+	Name         string        `json:",omitempty"`
+	Description  string        `json:",omitempty"`
+	StartDate    *DateTime     `json:",omitempty"`
+	EndDate      *DateTime     `json:",omitempty"`
+	IsCurrent    bool          `json:",omitempty"`
+	Effort       float32       `json:",omitempty"`
+	Project      *Project      `json:",omitempty"`
+	CustomFields []CustomField `json:",omitempty"`
+}
+
+// ReleaseResponse is a representation of the http response for a group of Releases
+type ReleaseResponse struct {
+	Items []Release
+	Next  string
+	Prev  string
+}
+
+// Iteration matches up with a targetprocess Iteration, a sprint that belongs to a Release
+type Iteration struct {
+	client *Client
+
+	ID           int32         `json:"Id,omitempty"`
+	Name         string        `json:",omitempty"`
+	Description  string        `json:",omitempty"`
+	StartDate    *DateTime     `json:",omitempty"`
+	EndDate      *DateTime     `json:",omitempty"`
+	IsCurrent    bool          `json:",omitempty"`
+	Velocity     float32       `json:",omitempty"`
+	Effort       float32       `json:",omitempty"`
+	Project      *Project      `json:",omitempty"`
+	Release      *Release      `json:",omitempty"`
+	CustomFields []CustomField `json:",omitempty"`
+}
+
+// IterationResponse is a representation of the http response for a group of Iterations
+type IterationResponse struct {
+	Items []Iteration
+	Next  string
+	Prev  string
+}
+
+// TeamIteration matches up with a targetprocess TeamIteration, a sprint that belongs to a Team
+type TeamIteration struct {
+	client *Client
+
+	ID           int32         `json:"Id,omitempty"`
+	Name         string        `json:",omitempty"`
+	Description  string        `json:",omitempty"`
+	StartDate    *DateTime     `json:",omitempty"`
+	EndDate      *DateTime     `json:",omitempty"`
+	IsCurrent    bool          `json:",omitempty"`
+	Velocity     float32       `json:",omitempty"`
+	Effort       float32       `json:",omitempty"`
+	Team         *Team         `json:",omitempty"`
+	CustomFields []CustomField `json:",omitempty"`
+}
+
+// TeamIterationResponse is a representation of the http response for a group of TeamIterations
+type TeamIterationResponse struct {
+	Items []TeamIteration
+	Next  string
+	Prev  string
+}
+
+// Assignable is any entity that can be assigned to people and planned in a sprint, ex. a UserStory, Task or Bug.
+// EntityType tells which one it is.
+type Assignable struct {
+	ID          int32        `json:"Id,omitempty"`
+	Name        string       `json:",omitempty"`
+	Description string       `json:",omitempty"`
+	EntityType  *EntityType  `json:",omitempty"`
+	EntityState *EntityState `json:",omitempty"`
+	Effort      float32      `json:",omitempty"`
+	TimeSpent   float32      `json:",omitempty"`
+	TimeRemain  float32      `json:",omitempty"`
+	Project     *Project     `json:",omitempty"`
+	Team        *Team        `json:",omitempty"`
+	Priority    *Priority    `json:",omitempty"`
+}
+
+// GetReleases will return all releases
+func (c *Client) GetReleases(filters ...QueryFilter) ([]Release, error) {
+	return c.GetReleasesCtx(c.context(), filters...)
+}
+
+// GetReleasesCtx is GetReleases with a context that can cancel the requests
+func (c *Client) GetReleasesCtx(ctx context.Context, filters ...QueryFilter) ([]Release, error) {
+	var ret []Release
+	it := c.NewIteratorCtx(ctx, "Releases", filters...)
+	for it.Next() {
+		item := Release{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetRelease will return a single release based on its name. If somehow there are releases with the same name,
+// this will only return the first one.
+func (c *Client) GetRelease(name string) (Release, error) {
+	return c.GetReleaseCtx(c.context(), name)
+}
+
+// GetReleaseCtx is GetRelease with a context that can cancel the requests
+func (c *Client) GetReleaseCtx(ctx context.Context, name string) (Release, error) {
+	ret := Release{}
+	err := c.getFirst(ctx, &ret, "Releases", fmt.Sprintf("release with name '%s'", name),
+		WhereExpr(Field("Name").Eq(name)),
+	)
+	if err != nil {
+		return Release{}, err
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetReleaseByID will return the Release with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetReleaseByID(id int32) (Release, error) {
+	return c.GetReleaseByIDCtx(c.context(), id)
+}
+
+// GetReleaseByIDCtx is GetReleaseByID with a context that can cancel the request
+func (c *Client) GetReleaseByIDCtx(ctx context.Context, id int32) (Release, error) {
+	ret := Release{}
+	err := c.GetByIDCtx(ctx, &ret, "Releases", id)
+	if err != nil {
+		return Release{}, errors.Wrap(err, fmt.Sprintf("error getting Release with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetReleaseAt will return the release of the project with the given name that is running on the day of at
+func (c *Client) GetReleaseAt(project string, at time.Time) (Release, error) {
+	return c.GetReleaseAtCtx(c.context(), project, at)
+}
+
+// GetReleaseAtCtx is GetReleaseAt with a context that can cancel the requests
+func (c *Client) GetReleaseAtCtx(ctx context.Context, project string, at time.Time) (Release, error) {
+	on := day(at)
+	ret := Release{}
+	err := c.getFirst(ctx, &ret, "Releases", fmt.Sprintf("release of project '%s' on %s", project, on.Format("2006-01-02")),
+		WhereExpr(
+			Field("Project.Name").Eq(project),
+			Field("StartDate").Lte(on),
+			Field("EndDate").Gte(on),
+		),
+	)
+	if err != nil {
+		return Release{}, err
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetCurrentRelease will return the release that the project with the given name is running today
+func (c *Client) GetCurrentRelease(project string) (Release, error) {
+	return c.GetCurrentReleaseCtx(c.context(), project)
+}
+
+// GetCurrentReleaseCtx is GetCurrentRelease with a context that can cancel the requests
+func (c *Client) GetCurrentReleaseCtx(ctx context.Context, project string) (Release, error) {
+	return c.GetReleaseAtCtx(ctx, project, time.Now())
+}
+
+// GetIterations will return all iterations
+func (c *Client) GetIterations(filters ...QueryFilter) ([]Iteration, error) {
+	return c.GetIterationsCtx(c.context(), filters...)
+}
+
+// GetIterationsCtx is GetIterations with a context that can cancel the requests
+func (c *Client) GetIterationsCtx(ctx context.Context, filters ...QueryFilter) ([]Iteration, error) {
+	var ret []Iteration
+	it := c.NewIteratorCtx(ctx, "Iterations", filters...)
+	for it.Next() {
+		item := Iteration{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetIteration will return a single iteration based on its name. If somehow there are iterations with the same name,
+// this will only return the first one.
+func (c *Client) GetIteration(name string) (Iteration, error) {
+	return c.GetIterationCtx(c.context(), name)
+}
+
+// GetIterationCtx is GetIteration with a context that can cancel the requests
+func (c *Client) GetIterationCtx(ctx context.Context, name string) (Iteration, error) {
+	ret := Iteration{}
+	err := c.getFirst(ctx, &ret, "Iterations", fmt.Sprintf("iteration with name '%s'", name),
+		WhereExpr(Field("Name").Eq(name)),
+	)
+	if err != nil {
+		return Iteration{}, err
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetIterationByID will return the Iteration with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetIterationByID(id int32) (Iteration, error) {
+	return c.GetIterationByIDCtx(c.context(), id)
+}
+
+// GetIterationByIDCtx is GetIterationByID with a context that can cancel the request
+func (c *Client) GetIterationByIDCtx(ctx context.Context, id int32) (Iteration, error) {
+	ret := Iteration{}
+	err := c.GetByIDCtx(ctx, &ret, "Iterations", id)
+	if err != nil {
+		return Iteration{}, errors.Wrap(err, fmt.Sprintf("error getting Iteration with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetIterationAt will return the iteration of the project with the given name that is running on the day of at
+func (c *Client) GetIterationAt(project string, at time.Time) (Iteration, error) {
+	return c.GetIterationAtCtx(c.context(), project, at)
+}
+
+// GetIterationAtCtx is GetIterationAt with a context that can cancel the requests
+func (c *Client) GetIterationAtCtx(ctx context.Context, project string, at time.Time) (Iteration, error) {
+	on := day(at)
+	ret := Iteration{}
+	err := c.getFirst(ctx, &ret, "Iterations", fmt.Sprintf("iteration of project '%s' on %s", project, on.Format("2006-01-02")),
+		WhereExpr(
+			Field("Project.Name").Eq(project),
+			Field("StartDate").Lte(on),
+			Field("EndDate").Gte(on),
+		),
+	)
+	if err != nil {
+		return Iteration{}, err
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetCurrentIteration will return the iteration that the project with the given name is running today
+func (c *Client) GetCurrentIteration(project string) (Iteration, error) {
+	return c.GetCurrentIterationCtx(c.context(), project)
+}
+
+// GetCurrentIterationCtx is GetCurrentIteration with a context that can cancel the requests
+func (c *Client) GetCurrentIterationCtx(ctx context.Context, project string) (Iteration, error) {
+	return c.GetIterationAtCtx(ctx, project, time.Now())
+}
+
+// GetTeamIterations will return all team iterations
+func (c *Client) GetTeamIterations(filters ...QueryFilter) ([]TeamIteration, error) {
+	return c.GetTeamIterationsCtx(c.context(), filters...)
+}
+
+// GetTeamIterationsCtx is GetTeamIterations with a context that can cancel the requests
+func (c *Client) GetTeamIterationsCtx(ctx context.Context, filters ...QueryFilter) ([]TeamIteration, error) {
+	var ret []TeamIteration
+	it := c.NewIteratorCtx(ctx, "TeamIterations", filters...)
+	for it.Next() {
+		item := TeamIteration{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetTeamIteration will return a single team iteration based on its name. If somehow there are team iterations
+// with the same name, this will only return the first one.
+func (c *Client) GetTeamIteration(name string) (TeamIteration, error) {
+	return c.GetTeamIterationCtx(c.context(), name)
+}
+
+// GetTeamIterationCtx is GetTeamIteration with a context that can cancel the requests
+func (c *Client) GetTeamIterationCtx(ctx context.Context, name string) (TeamIteration, error) {
+	ret := TeamIteration{}
+	err := c.getFirst(ctx, &ret, "TeamIterations", fmt.Sprintf("team iteration with name '%s'", name),
+		WhereExpr(Field("Name").Eq(name)),
+	)
+	if err != nil {
+		return TeamIteration{}, err
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetTeamIterationByID will return the TeamIteration with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetTeamIterationByID(id int32) (TeamIteration, error) {
+	return c.GetTeamIterationByIDCtx(c.context(), id)
+}
+
+// GetTeamIterationByIDCtx is GetTeamIterationByID with a context that can cancel the request
+func (c *Client) GetTeamIterationByIDCtx(ctx context.Context, id int32) (TeamIteration, error) {
+	ret := TeamIteration{}
+	err := c.GetByIDCtx(ctx, &ret, "TeamIterations", id)
+	if err != nil {
+		return TeamIteration{}, errors.Wrap(err, fmt.Sprintf("error getting TeamIteration with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetTeamIterationAt will return the iteration of the team with the given name that is running on the day of at
+func (c *Client) GetTeamIterationAt(team string, at time.Time) (TeamIteration, error) {
+	return c.GetTeamIterationAtCtx(c.context(), team, at)
+}
+
+// GetTeamIterationAtCtx is GetTeamIterationAt with a context that can cancel the requests
+func (c *Client) GetTeamIterationAtCtx(ctx context.Context, team string, at time.Time) (TeamIteration, error) {
+	on := day(at)
+	ret := TeamIteration{}
+	err := c.getFirst(ctx, &ret, "TeamIterations", fmt.Sprintf("iteration of team '%s' on %s", team, on.Format("2006-01-02")),
+		WhereExpr(
+			Field("Team.Name").Eq(team),
+			Field("StartDate").Lte(on),
+			Field("EndDate").Gte(on),
+		),
+	)
+	if err != nil {
+		return TeamIteration{}, err
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetCurrentTeamIteration will return the iteration that the team with the given name is running today
+func (c *Client) GetCurrentTeamIteration(team string) (TeamIteration, error) {
+	return c.GetCurrentTeamIterationCtx(c.context(), team)
+}
+
+// GetCurrentTeamIterationCtx is GetCurrentTeamIteration with a context that can cancel the requests
+func (c *Client) GetCurrentTeamIterationCtx(ctx context.Context, team string) (TeamIteration, error) {
+	return c.GetTeamIterationAtCtx(ctx, team, time.Now())
+}
+
+// getFirst decodes into out the first entity of entityType matching the filters, what describes
+// the entity being looked for in errors
+func (c *Client) getFirst(ctx context.Context, out interface{}, entityType, what string, filters ...QueryFilter) error {
+	page := iteratorPage{}
+	err := c.GetCtx(ctx, &page, entityType, nil, append(append([]QueryFilter{}, filters...), First())...)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error getting %s", what))
+	}
+	if len(page.Items) < 1 {
+		return fmt.Errorf("no items found")
+	}
+	if err := json.Unmarshal(page.Items[0], out); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("JSON decode failed on %s", what))
+	}
+	return nil
+}
+
+// GetAssignables will return every UserStory, Task, Bug and other assignable planned in the TeamIteration
+func (ti TeamIteration) GetAssignables(filters ...QueryFilter) ([]Assignable, error) {
+	return ti.GetAssignablesCtx(ti.client.context(), filters...)
+}
+
+// GetAssignablesCtx is GetAssignables with a context that can cancel the requests
+func (ti TeamIteration) GetAssignablesCtx(ctx context.Context, filters ...QueryFilter) ([]Assignable, error) {
+	if ti.client == nil {
+		return nil, fmt.Errorf("TeamIteration %d has no client, get it from a Client method", ti.ID)
+	}
+	filters = append([]QueryFilter{WhereExpr(Field("TeamIteration.Id").Eq(ti.ID))}, filters...)
+	return ti.client.GetAssignablesCtx(ctx, filters...)
+}
+
+// GetAssignables will return every UserStory, Task, Bug and other assignable planned in the Iteration
+func (i Iteration) GetAssignables(filters ...QueryFilter) ([]Assignable, error) {
+	return i.GetAssignablesCtx(i.client.context(), filters...)
+}
+
+// GetAssignablesCtx is GetAssignables with a context that can cancel the requests
+func (i Iteration) GetAssignablesCtx(ctx context.Context, filters ...QueryFilter) ([]Assignable, error) {
+	if i.client == nil {
+		return nil, fmt.Errorf("Iteration %d has no client, get it from a Client method", i.ID)
+	}
+	filters = append([]QueryFilter{WhereExpr(Field("Iteration.Id").Eq(i.ID))}, filters...)
+	return i.client.GetAssignablesCtx(ctx, filters...)
+}
+
+// GetAssignables will return all assignables, ex. every UserStory, Task and Bug
+func (c *Client) GetAssignables(filters ...QueryFilter) ([]Assignable, error) {
+	return c.GetAssignablesCtx(c.context(), filters...)
+}
+
+// GetAssignablesCtx is GetAssignables with a context that can cancel the requests
+func (c *Client) GetAssignablesCtx(ctx context.Context, filters ...QueryFilter) ([]Assignable, error) {
+	var ret []Assignable
+	it := c.NewIteratorCtx(ctx, "Assignables", filters...)
+	for it.Next() {
+		item := Assignable{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTeamIterationAt(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/TeamIterations/", r.URL.Path)
+		assert.Equal(t, "Team.Name == 'Platform' and StartDate <= '2020-06-17' and EndDate >= '2020-06-17'", r.URL.Query().Get("where"))
+		assert.Equal(t, "1", r.URL.Query().Get("take"))
+		_, _ = w.Write([]byte(`{"items": [{"id": 5, "name": "Sprint 12", "team": {"id": 3, "name": "Platform"}}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	ti, err := mockClient.GetTeamIterationAt("Platform", time.Date(2020, 6, 17, 15, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "Sprint 12", ti.Name)
+	assert.Equal(t, "Platform", ti.Team.Name)
+	assert.Equal(t, mockClient, ti.client)
+}
+
+func TestGetIterationAndReleaseAt(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Project.Name == 'Web' and StartDate <= '2020-06-17' and EndDate >= '2020-06-17'", r.URL.Query().Get("where"))
+		assert.Equal(t, "1", r.URL.Query().Get("take"))
+		switch r.URL.Path {
+		case "/api/v2/Iterations/":
+			_, _ = w.Write([]byte(`{"items": [{"id": 8, "name": "Iteration 3", "release": {"id": 2, "name": "2.0"}}]}`))
+		case "/api/v2/Releases/":
+			_, _ = w.Write([]byte(`{"items": [{"id": 2, "name": "2.0"}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	at := time.Date(2020, 6, 17, 15, 0, 0, 0, time.UTC)
+	i, err := mockClient.GetIterationAt("Web", at)
+	assert.NoError(t, err)
+	assert.Equal(t, "Iteration 3", i.Name)
+	assert.Equal(t, "2.0", i.Release.Name)
+	assert.Equal(t, mockClient, i.client)
+
+	r, err := mockClient.GetReleaseAt("Web", at)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), r.ID)
+	assert.Equal(t, mockClient, r.client)
+}
+
+func TestUserStorySetTeamIteration(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/TeamIterations/", r.URL.Path)
+		switch r.URL.Query().Get("where") {
+		case "Name == 'Sprint 12'":
+			_, _ = w.Write([]byte(`{"items": [{"id": 5, "name": "Sprint 12"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"items": []}`))
+		}
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	us := UserStory{client: mockClient}
+	assert.Error(t, us.SetTeamIteration("Sprint 13"))
+	assert.Nil(t, us.TeamIteration)
+
+	assert.NoError(t, us.SetTeamIteration("Sprint 12"))
+	assert.Equal(t, int32(5), us.TeamIteration.ID)
+}
+
+func TestUserStorySetRelease(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Releases/", r.URL.Path)
+		assert.Equal(t, "Name == '2.0'", r.URL.Query().Get("where"))
+		_, _ = w.Write([]byte(`{"items": [{"id": 2, "name": "2.0"}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	us := UserStory{client: mockClient}
+	assert.NoError(t, us.SetRelease("2.0"))
+	assert.Equal(t, int32(2), us.Release.ID)
+	assert.Equal(t, "2.0", us.Release.Name)
+}
+
+func TestTeamIterationGetAssignables(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Assignables/", r.URL.Path)
+		assert.Equal(t, "TeamIteration.Id == 5", r.URL.Query().Get("where"))
+		_, _ = w.Write([]byte(`{"items": [
+			{"id": 1, "entityType": {"id": 4, "name": "UserStory"}},
+			{"id": 2, "entityType": {"id": 8, "name": "Bug"}}
+		]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	items, err := TeamIteration{client: mockClient, ID: 5}.GetAssignables()
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "Bug", items[1].EntityType.Name)
+
+	_, err = TeamIteration{ID: 5}.GetAssignables()
+	assert.Error(t, err)
+}
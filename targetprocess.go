@@ -26,13 +26,14 @@
 //    }
 //    tpClient.Logger = logger
 //    userStories, err := tpClient.GetUserStories(
+//    	true,
 //    	// The Where() filter function takes in any queries the targetprocess API accepts
 //    	// Read about those here: https://dev.targetprocess.com/docs/sorting-and-filters
 //    	tp.Where("EntityState.Name != 'Done'"),
 //    	tp.Where("EntityState.Name != 'Backlog'"),
-//    	// Simlar to Where(), the Include() function will limit the
-//    	// response to a given list of fields
-//    	tp.Include("Team", "Name", "ModifyDate"),
+//    	// Simlar to Where(), the Select() function will limit the
+//    	// response to a given list of fields. Include() does the same for the v1 API.
+//    	tp.Select("team,name,modifyDate"),
 //    )
 //    if err != nil {
 //    	fmt.Println(err)
@@ -42,9 +43,9 @@
 //    fmt.Print(string(jsonBytes))
 //  }
 //
-// go-targetprocess includes some built-in structs that can be used for Users, Projects, Teams, and UserStories. You don't
+// go-targetprocess includes some built-in structs that can be used for Users, Projects, Teams, UserStories, Tasks, Bugs, Epics, and PortfolioEpics. You don't
 // have to use those though and can use the generic `Get()` method with a custom struct as the output for a response to be
-// JSON decoded into. Filtering functions (`Where()`, `Include()`, etc.) can be used in `Get()` just like they can in
+// JSON decoded into. Filtering functions (`Where()`, `Select()`, etc.) can be used in `Get()` just like they can in
 // any of the helper functions.
 //
 // Example:
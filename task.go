@@ -0,0 +1,262 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Task matches up with a targetprocess Task. Tasks break a UserStory down into smaller pieces of work.
+type Task struct {
+	client *Client
+
+	ID                  int32         `json:"Id,omitempty"`
+	Name                string        `json:",omitempty"`
+	Description         string        `json:",omitempty"`
+	StartDate           *DateTime     `json:",omitempty"`
+	EndDate             *DateTime     `json:",omitempty"`
+	CreateDate          *DateTime     `json:",omitempty"`
+	ModifyDate          *DateTime     `json:",omitempty"`
+	NumericPriority     float64       `json:",omitempty"`
+	CustomFields        []CustomField `json:",omitempty"`
+	Effort              float32       `json:",omitempty"`
+	EffortCompleted     float32       `json:",omitempty"`
+	EffortToDo          float32       `json:",omitempty"`
+	Project             *Project      `json:",omitempty"`
+	Progress            float32       `json:",omitempty"`
+	TimeSpent           float32       `json:",omitempty"`
+	TimeRemain          float32       `json:",omitempty"`
+	LastStateChangeDate *DateTime     `json:",omitempty"`
+	InitialEstimate     float32       `json:",omitempty"`
+	Assignments         *Assignments  `json:",omitempty"`
+	Team                *Team         `json:",omitempty"`
+	Priority            *Priority     `json:",omitempty"`
+	EntityState         *EntityState  `json:",omitempty"`
+	AssignedUser        *AssignedUser `json:",omitempty"`
+	UserStory           *UserStory    `json:",omitempty"`
+}
+
+// TaskList is a list of tasks. Can be used to create multiple tasks at once
+type TaskList struct {
+	client *Client
+	Tasks  []Task `json:"Tasks"`
+}
+
+// TaskResponse is a representation of the http response for a group of Tasks
+type TaskResponse struct {
+	Items []Task
+	Next  string
+	Prev  string
+}
+
+// NewTask will make a Task for the UserStory that this method is built off of
+func (us UserStory) NewTask(name, description string) (Task, error) {
+	if us.ID == 0 {
+		return Task{}, fmt.Errorf("UserStory %s has not been created yet", us.Name)
+	}
+	return Task{
+		client:      us.client,
+		Name:        name,
+		Description: description,
+		Project:     us.Project,
+		UserStory:   &UserStory{ID: us.ID},
+	}, nil
+}
+
+// GetTasks will return all tasks of the UserStory that this method is built off of
+func (us UserStory) GetTasks(filters ...QueryFilter) ([]Task, error) {
+	return us.GetTasksCtx(us.client.context(), filters...)
+}
+
+// GetTasksCtx is GetTasks with a context that can cancel the requests
+func (us UserStory) GetTasksCtx(ctx context.Context, filters ...QueryFilter) ([]Task, error) {
+	if us.client == nil {
+		return nil, fmt.Errorf("UserStory %d has no client, get it from a Client method", us.ID)
+	}
+	filters = append([]QueryFilter{WhereExpr(Field("UserStory.Id").Eq(us.ID))}, filters...)
+	return us.client.GetTasksCtx(ctx, true, filters...)
+}
+
+// SetAssignedUserID assigns the Task to a User based on their ID number
+func (t *Task) SetAssignedUserID(userID int32) {
+	u := User{
+		ID: userID,
+	}
+	au := Assignment{
+		GeneralUser: &u,
+	}
+	assignments := Assignments{Items: []Assignment{au}}
+	t.Assignments = &assignments
+}
+
+// GetTaskByID will return the Task with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetTaskByID(id int32) (Task, error) {
+	return c.GetTaskByIDCtx(c.context(), id)
+}
+
+// GetTaskByIDCtx is GetTaskByID with a context that can cancel the request
+func (c *Client) GetTaskByIDCtx(ctx context.Context, id int32) (Task, error) {
+	ret := Task{}
+	err := c.GetByIDCtx(ctx, &ret, "Tasks", id)
+	if err != nil {
+		return Task{}, errors.Wrap(err, fmt.Sprintf("error getting Task with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
+// GetTasks will return all tasks
+//
+// Use with caution if you have a lot and are not setting the MaxPerPage to a high number
+// as it could cause a lot of requests to the API and may take a long time.
+// If you know you have a lot you may want to include the QueryFilter MaxPerPage
+func (c *Client) GetTasks(page bool, filters ...QueryFilter) ([]Task, error) {
+	return c.GetTasksCtx(c.context(), page, filters...)
+}
+
+// GetTasksCtx is GetTasks with a context that can cancel the requests
+func (c *Client) GetTasksCtx(ctx context.Context, page bool, filters ...QueryFilter) ([]Task, error) {
+	var ret []Task
+	it := c.NewIteratorCtx(ctx, "Tasks", filters...)
+	if !page {
+		it.MaxPages(1)
+	}
+	for it.Next() {
+		item := Task{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// Create takes a Task struct and crafts a POST to make it so in TP
+// it returns the ID of the Task created as well as a link to the entity
+// on the Target Process frontend
+func (t Task) Create() (int32, string, error) {
+	return t.CreateCtx(t.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the requests
+func (t Task) CreateCtx(ctx context.Context) (int32, string, error) {
+	client := t.client
+	resp := &struct {
+		ID int32 `json:"Id"`
+	}{}
+	body, err := json.Marshal(t)
+	if err != nil {
+		return 0, "", errors.Wrap(err, fmt.Sprintf("error marshaling POST body for Task %s", t.Name))
+	}
+
+	client.debugLog(fmt.Sprintf("Attempting to POST Task: %+v", t))
+	err = client.PostCtx(ctx, resp, "Task", nil, body)
+	if err != nil {
+		return 0, "", errors.Wrap(err, fmt.Sprintf("error POSTing Task %s", t.Name))
+	}
+	client.debugLog("[targetprocess] Successfully POSTed Task")
+	client.debugLog(fmt.Sprintf("[targetprocess] Task created. ID: %d", resp.ID))
+	link := client.EntityURL(resp.ID)
+	return resp.ID, link, nil
+}
+
+// Update sends only the given fields of the Task to Targetprocess, leaving every other field untouched,
+// and returns the Task as it is after the update.
+// Fields are named as in the JSON, ex. t.Update("TimeRemain", "EntityState")
+func (t Task) Update(fields ...string) (Task, error) {
+	return t.UpdateCtx(t.client.context(), fields...)
+}
+
+// UpdateCtx is Update with a context that can cancel the request
+func (t Task) UpdateCtx(ctx context.Context, fields ...string) (Task, error) {
+	client := t.client
+	if client == nil {
+		return Task{}, fmt.Errorf("Task %d has no client, get it from a Client method", t.ID)
+	}
+	body, err := updateBody(t, t.ID, fields)
+	if err != nil {
+		return Task{}, errors.Wrap(err, fmt.Sprintf("error building update for Task %d", t.ID))
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Attempting to update Task %d: %s", t.ID, body))
+	ret := Task{}
+	err = client.UpdateCtx(ctx, &ret, "Tasks", t.ID, body)
+	if err != nil {
+		return Task{}, errors.Wrap(err, fmt.Sprintf("error updating Task %d", t.ID))
+	}
+	ret.client = client
+	return ret, nil
+}
+
+// Delete will delete the Task in Targetprocess
+func (t Task) Delete() error {
+	return t.DeleteCtx(t.client.context())
+}
+
+// DeleteCtx is Delete with a context that can cancel the request
+func (t Task) DeleteCtx(ctx context.Context) error {
+	if t.client == nil {
+		return fmt.Errorf("Task %d has no client, get it from a Client method", t.ID)
+	}
+	return t.client.DeleteCtx(ctx, "Tasks", t.ID)
+}
+
+// NewTaskList returns a TaskList from a list of tasks.
+// Used for batch POSTing of Tasks
+func (c *Client) NewTaskList(list []Task) *TaskList {
+	return &TaskList{
+		client: c,
+		Tasks:  list,
+	}
+}
+
+// Create posts a list of tasks to create them
+// returns a list of entity IDs along with a list of links to them
+func (tl TaskList) Create() ([]int32, []string, error) {
+	return tl.CreateCtx(tl.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the requests
+func (tl TaskList) CreateCtx(ctx context.Context) ([]int32, []string, error) {
+	client := tl.client
+	resp := &TaskResponse{}
+	body, err := json.Marshal(tl.Tasks)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, fmt.Sprintf("error marshaling POST body for TaskList %v", tl))
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Attempting to POST Task: %+v", tl))
+	err = client.PostCtx(ctx, resp, "Tasks/bulk", nil, body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, fmt.Sprintf("error POSTing TaskList %v", tl))
+	}
+	client.debugLog("[targetprocess] Successfully POSTed TaskList")
+
+	var (
+		ret   []int32
+		links []string
+	)
+
+	for _, task := range resp.Items {
+		ret = append(ret, task.ID)
+		links = append(links, client.EntityURL(task.ID))
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Tasks created with IDs: %v", ret))
+	return ret, links, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskListCreate(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/Tasks/bulk/", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `[
+			{"Name": "Write code", "Project": {"Id": 7}, "UserStory": {"Id": 42}},
+			{"Name": "Write tests", "Project": {"Id": 7}, "UserStory": {"Id": 42}}
+		]`, string(body))
+		_, _ = w.Write([]byte(`{"Items": [{"Id": 100}, {"Id": 101}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	us := UserStory{client: mockClient, ID: 42, Project: &Project{ID: 7}}
+	code, err := us.NewTask("Write code", "")
+	assert.NoError(t, err)
+	tests, err := us.NewTask("Write tests", "")
+	assert.NoError(t, err)
+
+	ids, links, err := mockClient.NewTaskList([]Task{code, tests}).Create()
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{100, 101}, ids)
+	assert.Equal(t, "https://example.tpondemand.com/entity/101/RestUI/board.aspx", links[1])
+
+	_, err = UserStory{client: mockClient}.NewTask("Not created", "")
+	assert.Error(t, err)
+}
+
+func TestUserStoryGetTasks(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Tasks/", r.URL.Path)
+		assert.Equal(t, "UserStory.Id == 42", r.URL.Query().Get("where"))
+		_, _ = w.Write([]byte(`{"items": [{"id": 1, "timeSpent": 2.5, "userStory": {"id": 42}}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	tasks, err := UserStory{client: mockClient, ID: 42}.GetTasks()
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, float32(2.5), tasks[0].TimeSpent)
+	assert.Equal(t, int32(42), tasks[0].UserStory.ID)
+	assert.Equal(t, mockClient, tasks[0].client)
+}
@@ -15,6 +15,7 @@
 package targetprocess
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -27,10 +28,10 @@ type Team struct {
 	ID              int32         `json:"Id,omitempty"`
 	Name            string        `json:",omitempty"`
 	Description     string        `json:",omitempty"`
-	StartDate       DateTime      `json:",omitempty"`
-	EndDate         DateTime      `json:",omitempty"`
-	CreateDate      DateTime      `json:",omitempty"`
-	ModifyDate      DateTime      `json:",omitempty"`
+	StartDate       *DateTime     `json:",omitempty"`
+	EndDate         *DateTime     `json:",omitempty"`
+	CreateDate      *DateTime     `json:",omitempty"`
+	ModifyDate      *DateTime     `json:",omitempty"`
 	NumericPriority float64       `json:",omitempty"`
 	CustomFields    []CustomField `json:",omitempty"`
 	Abbreviation    string        `json:",omitempty"`
@@ -46,10 +47,15 @@ type TeamResponse struct {
 // GetTeam will return a single team based on its name. If somehow there are teams with the same name,
 // this will only return the first one.
 func (c *Client) GetTeam(name string) (Team, error) {
+	return c.GetTeamCtx(c.context(), name)
+}
+
+// GetTeamCtx is GetTeam with a context that can cancel the requests
+func (c *Client) GetTeamCtx(ctx context.Context, name string) (Team, error) {
 	ret := Team{}
 	out := TeamResponse{}
-	err := c.Get(&out, "Team", nil,
-		Where(fmt.Sprintf("Name == '%s'", name)),
+	err := c.GetCtx(ctx, &out, "Team", nil,
+		WhereExpr(Field("Name").Eq(name)),
 		First(),
 	)
 	if err != nil {
@@ -63,12 +69,47 @@ func (c *Client) GetTeam(name string) (Team, error) {
 	return ret, nil
 }
 
+// GetTeamByID will return the Team with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetTeamByID(id int32) (Team, error) {
+	return c.GetTeamByIDCtx(c.context(), id)
+}
+
+// GetTeamByIDCtx is GetTeamByID with a context that can cancel the request
+func (c *Client) GetTeamByIDCtx(ctx context.Context, id int32) (Team, error) {
+	ret := Team{}
+	err := c.GetByIDCtx(ctx, &ret, "Teams", id)
+	if err != nil {
+		return Team{}, errors.Wrap(err, fmt.Sprintf("error getting Team with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
 // NewUserStory will make a UserStory assigned to the Team that this method is built off of
 func (t Team) NewUserStory(name, description, project string) (UserStory, error) {
-	us, err := NewUserStory(t.client, name, description, project)
+	return t.NewUserStoryCtx(t.client.context(), name, description, project)
+}
+
+// NewUserStoryCtx is NewUserStory with a context that can cancel the requests
+func (t Team) NewUserStoryCtx(ctx context.Context, name, description, project string) (UserStory, error) {
+	us, err := NewUserStoryCtx(ctx, t.client, name, description, project)
 	if err != nil {
 		return UserStory{}, err
 	}
 	us.Team = &t
 	return us, nil
 }
+
+// Delete will delete the Team in Targetprocess
+func (t Team) Delete() error {
+	return t.DeleteCtx(t.client.context())
+}
+
+// DeleteCtx is Delete with a context that can cancel the request
+func (t Team) DeleteCtx(ctx context.Context) error {
+	if t.client == nil {
+		return fmt.Errorf("Team %d has no client, get it from a Client method", t.ID)
+	}
+	return t.client.DeleteCtx(ctx, "Teams", t.ID)
+}
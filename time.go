@@ -0,0 +1,230 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Time matches up with a targetprocess Time, hours a User logged against an Assignable.
+// Spent and Remain are always sent, as a Remain of 0 marks the work as done.
+type Time struct {
+	client *Client
+
+	ID          int32       `json:"Id,omitempty"`
+	Description string      `json:",omitempty"`
+	Spent       float32     `json:"Spent"`
+	Remain      float32     `json:"Remain"`
+	Date        *DateTime   `json:",omitempty"`
+	CreateDate  *DateTime   `json:",omitempty"`
+	User        *User       `json:",omitempty"`
+	Role        *Role       `json:",omitempty"`
+	Assignable  *Assignable `json:",omitempty"`
+	Project     *Project    `json:",omitempty"`
+}
+
+// TimeResponse is a representation of the http response for a group of Times
+type TimeResponse struct {
+	Items []Time
+	Next  string
+	Prev  string
+}
+
+// Role matches up with a targetprocess Role, ex. Developer or QA Engineer
+type Role struct {
+	ID   int32  `json:"Id,omitempty"`
+	Name string `json:",omitempty"`
+}
+
+// SpentTime is the time a User spent on an Assignable over a period
+type SpentTime struct {
+	User       *User
+	Assignable *Assignable
+	Spent      float64
+}
+
+// Between is a QueryFilter for entries with a Date on or after the day of from and before the day of to,
+// ex. Between(june1, july1) for the month of June
+func Between(from, to time.Time) QueryFilter {
+	return WhereExpr(
+		Field("Date").Gte(day(from)),
+		Field("Date").Lt(day(to)),
+	)
+}
+
+// GetTimes will return all time entries
+func (c *Client) GetTimes(filters ...QueryFilter) ([]Time, error) {
+	return c.GetTimesCtx(c.context(), filters...)
+}
+
+// GetTimesCtx is GetTimes with a context that can cancel the requests
+func (c *Client) GetTimesCtx(ctx context.Context, filters ...QueryFilter) ([]Time, error) {
+	var ret []Time
+	it := c.NewIteratorCtx(ctx, "Times", filters...)
+	for it.Next() {
+		item := Time{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetUserTimes will return the time entries logged by the User with the given ID on or after the day
+// of from and before the day of to
+func (c *Client) GetUserTimes(userID int32, from, to time.Time, filters ...QueryFilter) ([]Time, error) {
+	return c.GetUserTimesCtx(c.context(), userID, from, to, filters...)
+}
+
+// GetUserTimesCtx is GetUserTimes with a context that can cancel the requests
+func (c *Client) GetUserTimesCtx(ctx context.Context, userID int32, from, to time.Time, filters ...QueryFilter) ([]Time, error) {
+	filters = append([]QueryFilter{WhereExpr(Field("User.Id").Eq(userID)), Between(from, to)}, filters...)
+	return c.GetTimesCtx(ctx, filters...)
+}
+
+// GetTotalSpent will return the sum of the time spent in the entries on or after the day of from and
+// before the day of to. It is computed by the API in a single request.
+func (c *Client) GetTotalSpent(from, to time.Time, filters ...QueryFilter) (float64, error) {
+	return c.GetTotalSpentCtx(c.context(), from, to, filters...)
+}
+
+// GetTotalSpentCtx is GetTotalSpent with a context that can cancel the request
+func (c *Client) GetTotalSpentCtx(ctx context.Context, from, to time.Time, filters ...QueryFilter) (float64, error) {
+	filters = append([]QueryFilter{Between(from, to)}, filters...)
+	return c.AggregateCtx(ctx, "Times", Sum("Spent"), filters...)
+}
+
+// GetSpentTime will return the time spent per User per Assignable on or after the day of from and
+// before the day of to. Only the ID of the User and the Assignable is set.
+//
+// The sums are computed by the API in a single request, grouping the entries by user and then grouping
+// each user's entries by assignable. Use GetTotalSpent when a single total is enough.
+func (c *Client) GetSpentTime(from, to time.Time, filters ...QueryFilter) ([]SpentTime, error) {
+	return c.GetSpentTimeCtx(c.context(), from, to, filters...)
+}
+
+// GetSpentTimeCtx is GetSpentTime with a context that can cancel the request
+func (c *Client) GetSpentTimeCtx(ctx context.Context, from, to time.Time, filters ...QueryFilter) ([]SpentTime, error) {
+	out := struct {
+		Users []struct {
+			Key         *int32 `json:"key"`
+			Assignables []struct {
+				Key   *int32   `json:"key"`
+				Spent *float64 `json:"spent"`
+			} `json:"assignables"`
+		} `json:"users"`
+	}{}
+	spent := Sum("Spent")
+	result := fmt.Sprintf("users:groupBy(User.Id).select({key,assignables:groupBy(Assignable.Id).select({key,spent:%s})})", spent)
+	filters = append(append([]QueryFilter{Between(from, to)}, filters...), Result(result))
+	if err := c.GetCtx(ctx, &out, "Times", nil, filters...); err != nil {
+		return nil, errors.Wrap(err, "error getting time spent")
+	}
+	var ret []SpentTime
+	for _, user := range out.Users {
+		for _, assignable := range user.Assignables {
+			s := SpentTime{}
+			if user.Key != nil {
+				s.User = &User{ID: *user.Key}
+			}
+			if assignable.Key != nil {
+				s.Assignable = &Assignable{ID: *assignable.Key}
+			}
+			if assignable.Spent != nil {
+				s.Spent = *assignable.Spent
+			}
+			ret = append(ret, s)
+		}
+	}
+	return ret, nil
+}
+
+// LogTime logs time the User with the given ID spent on the assignable with the given ID, ex. a UserStory,
+// Task or Bug, and returns the ID of the time entry. remain is the time left to finish the work.
+func (c *Client) LogTime(assignableID, userID int32, spent, remain float32, date time.Time, note string) (int32, error) {
+	return c.LogTimeCtx(c.context(), assignableID, userID, spent, remain, date, note)
+}
+
+// LogTimeCtx is LogTime with a context that can cancel the request
+func (c *Client) LogTimeCtx(ctx context.Context, assignableID, userID int32, spent, remain float32, date time.Time, note string) (int32, error) {
+	t := Time{
+		Description: note,
+		Spent:       spent,
+		Remain:      remain,
+		Date:        NewDateTime(date),
+		User:        &User{ID: userID},
+		Assignable:  &Assignable{ID: assignableID},
+	}
+	resp := &struct {
+		ID int32 `json:"Id"`
+	}{}
+	body, err := json.Marshal(t)
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("error marshaling POST body for Time on %d", assignableID))
+	}
+	c.debugLog(fmt.Sprintf("[targetprocess] Attempting to POST Time: %s", body))
+	err = c.PostCtx(ctx, resp, "Times", nil, body)
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("error POSTing Time on %d", assignableID))
+	}
+	c.debugLog(fmt.Sprintf("[targetprocess] Time created. ID: %d", resp.ID))
+	return resp.ID, nil
+}
+
+// LogTime logs time the User with the given ID spent on the UserStory. See Client.LogTime
+func (us UserStory) LogTime(userID int32, spent, remain float32, date time.Time, note string) (int32, error) {
+	return us.LogTimeCtx(us.client.context(), userID, spent, remain, date, note)
+}
+
+// LogTimeCtx is LogTime with a context that can cancel the request
+func (us UserStory) LogTimeCtx(ctx context.Context, userID int32, spent, remain float32, date time.Time, note string) (int32, error) {
+	if us.client == nil || us.ID == 0 {
+		return 0, fmt.Errorf("UserStory %d has no client or has not been created yet", us.ID)
+	}
+	return us.client.LogTimeCtx(ctx, us.ID, userID, spent, remain, date, note)
+}
+
+// LogTime logs time the User with the given ID spent on the Task. See Client.LogTime
+func (t Task) LogTime(userID int32, spent, remain float32, date time.Time, note string) (int32, error) {
+	return t.LogTimeCtx(t.client.context(), userID, spent, remain, date, note)
+}
+
+// LogTimeCtx is LogTime with a context that can cancel the request
+func (t Task) LogTimeCtx(ctx context.Context, userID int32, spent, remain float32, date time.Time, note string) (int32, error) {
+	if t.client == nil || t.ID == 0 {
+		return 0, fmt.Errorf("Task %d has no client or has not been created yet", t.ID)
+	}
+	return t.client.LogTimeCtx(ctx, t.ID, userID, spent, remain, date, note)
+}
+
+// LogTime logs time the User with the given ID spent on the Bug. See Client.LogTime
+func (b Bug) LogTime(userID int32, spent, remain float32, date time.Time, note string) (int32, error) {
+	return b.LogTimeCtx(b.client.context(), userID, spent, remain, date, note)
+}
+
+// LogTimeCtx is LogTime with a context that can cancel the request
+func (b Bug) LogTimeCtx(ctx context.Context, userID int32, spent, remain float32, date time.Time, note string) (int32, error) {
+	if b.client == nil || b.ID == 0 {
+		return 0, fmt.Errorf("Bug %d has no client or has not been created yet", b.ID)
+	}
+	return b.client.LogTimeCtx(ctx, b.ID, userID, spent, remain, date, note)
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	june = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	july = time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestUserStoryLogTime(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/Times/", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{
+			"Description": "pairing",
+			"Spent": 2.5,
+			"Remain": 0,
+			"Date": "/Date(1592386200000+0000)/",
+			"User": {"Id": 3},
+			"Assignable": {"Id": 42}
+		}`, string(body))
+		_, _ = w.Write([]byte(`{"Id": 100}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	id, err := UserStory{client: mockClient, ID: 42}.LogTime(3, 2.5, 0, time.Date(2020, 6, 17, 9, 30, 0, 0, time.UTC), "pairing")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(100), id)
+
+	_, err = UserStory{client: mockClient}.LogTime(3, 1, 0, june, "")
+	assert.Error(t, err)
+}
+
+func TestGetUserTimes(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Times/", r.URL.Path)
+		assert.Equal(t, "User.Id == 3 and Date >= '2020-06-01' and Date < '2020-07-01'", r.URL.Query().Get("where"))
+		_, _ = w.Write([]byte(`{"items": [{"id": 1, "spent": 4, "remain": 2, "user": {"id": 3}, "role": {"id": 1, "name": "Developer"}}]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	times, err := mockClient.GetUserTimes(3, june, july)
+	assert.NoError(t, err)
+	assert.Len(t, times, 1)
+	assert.Equal(t, float32(4), times[0].Spent)
+	assert.Equal(t, "Developer", times[0].Role.Name)
+}
+
+func TestGetSpentTime(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/Times/", r.URL.Path)
+		assert.Equal(t, "Date >= '2020-06-01' and Date < '2020-07-01'", r.URL.Query().Get("where"))
+		if r.URL.Query().Get("result") == "{sum:sum(Spent)}" {
+			_, _ = w.Write([]byte(`{"sum": 9.5}`))
+			return
+		}
+		assert.Equal(t, "{users:groupBy(User.Id).select({key,assignables:groupBy(Assignable.Id).select({key,spent:sum(Spent)})})}", r.URL.Query().Get("result"))
+		_, _ = w.Write([]byte(`{"users": [
+			{"key": 3, "assignables": [{"key": 42, "spent": 5}, {"key": 43, "spent": 3}]},
+			{"key": 4, "assignables": [{"key": 42, "spent": 1.5}]},
+			{"key": null, "assignables": [{"key": 44, "spent": null}]}
+		]}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	spent, err := mockClient.GetSpentTime(june, july)
+	assert.NoError(t, err)
+	assert.Len(t, spent, 4)
+	assert.Equal(t, int32(3), spent[0].User.ID)
+	assert.Equal(t, int32(42), spent[0].Assignable.ID)
+	assert.Equal(t, 5.0, spent[0].Spent)
+	assert.Equal(t, int32(43), spent[1].Assignable.ID)
+	assert.Equal(t, 3.0, spent[1].Spent)
+	assert.Equal(t, int32(4), spent[2].User.ID)
+	assert.Equal(t, 1.5, spent[2].Spent)
+	assert.Nil(t, spent[3].User)
+	assert.Equal(t, 0.0, spent[3].Spent)
+
+	total, err := mockClient.GetTotalSpent(june, july)
+	assert.NoError(t, err)
+	assert.Equal(t, 9.5, total)
+}
@@ -0,0 +1,258 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// TransitionOption configures a call to TransitionTo
+type TransitionOption func(o *transitionOptions)
+
+type transitionOptions struct {
+	comment    string
+	workflowID int32
+}
+
+// WithComment adds a comment to the entity along with the state change.
+// It is required when moving to a state that has IsCommentRequired set.
+func WithComment(comment string) TransitionOption {
+	return func(o *transitionOptions) {
+		o.comment = comment
+	}
+}
+
+// InWorkflow picks the state from the given workflow instead of the workflow of the entity's team.
+// It is only needed to move an entity to a state of a workflow other than its team's.
+func InWorkflow(workflowID int32) TransitionOption {
+	return func(o *transitionOptions) {
+		o.workflowID = workflowID
+	}
+}
+
+// transition moves the entity of entityType (ex. UserStory) with the given id to the state named stateName.
+// update is called with the resolved state and must save it on the entity.
+func (c *Client) transition(ctx context.Context, entityType string, id int32, project *Project, team *Team,
+	current *EntityState, stateName string, opts []TransitionOption, update func(state EntityState) error) error {
+	if c == nil {
+		return fmt.Errorf("%s %d has no client, get it from a Client method", entityType, id)
+	}
+	o := transitionOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	state, err := c.resolveTransition(ctx, entityType, project, team, current, stateName, o)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("cannot move %s %d to '%s'", entityType, id, stateName))
+	}
+	if err := update(state); err != nil {
+		return err
+	}
+	if o.comment != "" {
+		if _, err := c.AddCommentCtx(ctx, id, o.comment); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("%s %d moved to '%s' but adding the comment failed", entityType, id, stateName))
+		}
+	}
+	return nil
+}
+
+// resolveTransition finds the EntityState named stateName for entityType (ex. UserStory) in the
+// process of project, and checks the transition from current to it is allowed. A state of the
+// workflow that team follows in project wins over a process level state with the same name.
+func (c *Client) resolveTransition(ctx context.Context, entityType string, project *Project, team *Team, current *EntityState, stateName string, o transitionOptions) (EntityState, error) {
+	if project == nil || project.ID == 0 {
+		return EntityState{}, fmt.Errorf("%s has no project, cannot determine its process", entityType)
+	}
+	if project.Process == nil || project.client == nil {
+		p, err := c.GetProjectByIDCtx(ctx, project.ID)
+		if err != nil {
+			return EntityState{}, err
+		}
+		project = &p
+	}
+	if project.Process == nil {
+		return EntityState{}, fmt.Errorf("project %s has no process", project.Name)
+	}
+	process, err := project.GetProcessCtx(ctx)
+	if err != nil {
+		return EntityState{}, errors.Wrap(err, fmt.Sprintf("error getting process of project %s", project.Name))
+	}
+
+	workflowID := o.workflowID
+	if workflowID == 0 {
+		workflowID, err = c.teamWorkflowID(ctx, entityType, project, team)
+		if err != nil {
+			return EntityState{}, err
+		}
+	}
+
+	states, err := c.GetEntityStatesCtx(ctx,
+		WhereExpr(Field("Process.Id").Eq(process.ID)),
+		WhereExpr(Field("EntityType.Name").Eq(entityType)),
+		WhereExpr(Field("Name").Eq(stateName)),
+		Select("id,name,numericPriority,isFinal,isCommentRequired,parentEntityState,workflow,entityType"),
+	)
+	if err != nil {
+		return EntityState{}, errors.Wrap(err, fmt.Sprintf("error getting %s states of process %s", entityType, process.Name))
+	}
+
+	var candidates []EntityState
+	if workflowID != 0 {
+		for _, state := range states {
+			if state.Workflow != nil && state.Workflow.ID == workflowID {
+				candidates = append(candidates, state)
+			}
+		}
+		if len(candidates) == 0 && o.workflowID != 0 {
+			return EntityState{}, fmt.Errorf("no %s state named '%s' in workflow %d", entityType, stateName, workflowID)
+		}
+	}
+	if len(candidates) == 0 {
+		// the team follows the process workflow, or its workflow only adds sub-states under other names
+		for _, state := range states {
+			if state.ParentEntityState == nil {
+				candidates = append(candidates, state)
+			}
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return EntityState{}, fmt.Errorf("no %s state named '%s' in process %s", entityType, stateName, process.Name)
+	case 1:
+	default:
+		return EntityState{}, fmt.Errorf("state name '%s' is ambiguous in process %s, use InWorkflow to pick one", stateName, process.Name)
+	}
+	target := candidates[0]
+
+	if current != nil && current.ID == target.ID {
+		return EntityState{}, fmt.Errorf("%s is already in state '%s'", entityType, target.Name)
+	}
+	if target.IsCommentRequired && o.comment == "" {
+		return EntityState{}, fmt.Errorf("state '%s' requires a comment, use WithComment", target.Name)
+	}
+	return target, nil
+}
+
+// teamWorkflowID returns the ID of the workflow that team follows for entityType in project,
+// or 0 when there is no team or it follows the workflow of the process
+func (c *Client) teamWorkflowID(ctx context.Context, entityType string, project *Project, team *Team) (int32, error) {
+	if team == nil || team.ID == 0 {
+		return 0, nil
+	}
+	workflows := Field("Workflows").Where(Field("EntityType.Name").Eq(entityType))
+	if workflows.err != nil {
+		return 0, workflows.err
+	}
+	out := struct {
+		Items []struct {
+			Workflows []Workflow
+		}
+	}{}
+	err := c.GetCtx(ctx, &out, "TeamProjects", nil,
+		WhereExpr(Field("Team.Id").Eq(team.ID), Field("Project.Id").Eq(project.ID)),
+		Select(fmt.Sprintf("workflows:%s.Select({id,name})", workflows)),
+		First(),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("error getting the %s workflow of team %d in project %s", entityType, team.ID, project.Name))
+	}
+	if len(out.Items) < 1 || len(out.Items[0].Workflows) < 1 {
+		return 0, nil
+	}
+	return out.Items[0].Workflows[0].ID, nil
+}
+
+// assignedTeam is the team responsible for an entity, falling back to its Team field
+func assignedTeam(responsible *TeamAssignment, team *Team) *Team {
+	if responsible != nil && responsible.Team != nil {
+		return responsible.Team
+	}
+	return team
+}
+
+// TransitionTo moves the UserStory to the state with the given name in the workflow its team follows, or
+// else in the workflow of its project's process, and returns the UserStory as it is after the update.
+func (us UserStory) TransitionTo(stateName string, opts ...TransitionOption) (UserStory, error) {
+	return us.TransitionToCtx(us.client.context(), stateName, opts...)
+}
+
+// TransitionToCtx is TransitionTo with a context that can cancel the requests
+func (us UserStory) TransitionToCtx(ctx context.Context, stateName string, opts ...TransitionOption) (UserStory, error) {
+	var updated UserStory
+	err := us.client.transition(ctx, "UserStory", us.ID, us.Project, assignedTeam(us.ResponsibleTeam, us.Team), us.EntityState, stateName, opts, func(state EntityState) error {
+		us.EntityState = &EntityState{ID: state.ID}
+		var err error
+		updated, err = us.UpdateCtx(ctx, "EntityState")
+		return err
+	})
+	return updated, err
+}
+
+// TransitionTo moves the Feature to the state with the given name in the workflow of its project's
+// process, and returns the Feature as it is after the update.
+func (f Feature) TransitionTo(stateName string, opts ...TransitionOption) (Feature, error) {
+	return f.TransitionToCtx(f.client.context(), stateName, opts...)
+}
+
+// TransitionToCtx is TransitionTo with a context that can cancel the requests
+func (f Feature) TransitionToCtx(ctx context.Context, stateName string, opts ...TransitionOption) (Feature, error) {
+	var updated Feature
+	err := f.client.transition(ctx, "Feature", f.ID, f.Project, nil, f.EntityState, stateName, opts, func(state EntityState) error {
+		f.EntityState = &EntityState{ID: state.ID}
+		var err error
+		updated, err = f.UpdateCtx(ctx, "EntityState")
+		return err
+	})
+	return updated, err
+}
+
+// TransitionTo moves the Bug to the state with the given name in the workflow its team follows, or
+// else in the workflow of its project's process, and returns the Bug as it is after the update.
+func (b Bug) TransitionTo(stateName string, opts ...TransitionOption) (Bug, error) {
+	return b.TransitionToCtx(b.client.context(), stateName, opts...)
+}
+
+// TransitionToCtx is TransitionTo with a context that can cancel the requests
+func (b Bug) TransitionToCtx(ctx context.Context, stateName string, opts ...TransitionOption) (Bug, error) {
+	var updated Bug
+	err := b.client.transition(ctx, "Bug", b.ID, b.Project, assignedTeam(b.ResponsibleTeam, b.Team), b.EntityState, stateName, opts, func(state EntityState) error {
+		b.EntityState = &EntityState{ID: state.ID}
+		var err error
+		updated, err = b.UpdateCtx(ctx, "EntityState")
+		return err
+	})
+	return updated, err
+}
+
+// TransitionTo moves the Task to the state with the given name in the workflow its team follows, or
+// else in the workflow of its project's process, and returns the Task as it is after the update.
+func (t Task) TransitionTo(stateName string, opts ...TransitionOption) (Task, error) {
+	return t.TransitionToCtx(t.client.context(), stateName, opts...)
+}
+
+// TransitionToCtx is TransitionTo with a context that can cancel the requests
+func (t Task) TransitionToCtx(ctx context.Context, stateName string, opts ...TransitionOption) (Task, error) {
+	var updated Task
+	err := t.client.transition(ctx, "Task", t.ID, t.Project, t.Team, t.EntityState, stateName, opts, func(state EntityState) error {
+		t.EntityState = &EntityState{ID: state.ID}
+		var err error
+		updated, err = t.UpdateCtx(ctx, "EntityState")
+		return err
+	})
+	return updated, err
+}
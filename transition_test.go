@@ -0,0 +1,228 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// workflowHandler serves a project with process 3, whose entityType states are Open (10), In Progress (11),
+// Done (12, comment required) and Review, which exists at process level (13) and as a sub-state of team
+// workflow 5 (14). Team 3 follows workflow 5 in the project. Updates of entity 42 in resource are echoed
+// back and comments are recorded.
+func workflowHandler(t *testing.T, entityType, resource string, comments *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/Projects/7":
+			_, _ = w.Write([]byte(`{"Id": 7, "Name": "Project", "Process": {"Id": 3}}`))
+		case r.URL.Path == "/api/v2/Process/":
+			assert.Equal(t, "Id == 3", r.URL.Query().Get("where"))
+			_, _ = w.Write([]byte(`{"items": [{"id": 3, "name": "Scrum"}]}`))
+		case r.URL.Path == "/api/v2/EntityState/":
+			where := r.URL.Query().Get("where")
+			assert.True(t, strings.HasPrefix(where, "Process.Id == 3 and EntityType.Name == '"+entityType+"' and "), where)
+			states := map[string]string{
+				"Name == 'In Progress'": `[{"id": 11, "name": "In Progress"}]`,
+				"Name == 'Done'":        `[{"id": 12, "name": "Done", "isFinal": true, "isCommentRequired": true}]`,
+				"Name == 'Review'":      `[{"id": 13, "name": "Review"}, {"id": 14, "name": "Review", "parentEntityState": {"id": 13}, "workflow": {"id": 5}}]`,
+				"Name == 'Open'":        `[{"id": 10, "name": "Open"}]`,
+			}
+			items := "[]"
+			for clause, found := range states {
+				if strings.HasSuffix(where, clause) {
+					items = found
+				}
+			}
+			_, _ = w.Write([]byte(`{"items": ` + items + `}`))
+		case r.URL.Path == "/api/v2/TeamProjects/":
+			assert.Equal(t, "{workflows:Workflows.Where(EntityType.Name == '"+entityType+"').Select({id,name})}", r.URL.Query().Get("select"))
+			switch r.URL.Query().Get("where") {
+			case "Team.Id == 3 and Project.Id == 7":
+				_, _ = w.Write([]byte(`{"items": [{"workflows": [{"id": 5, "name": "Platform"}]}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"items": [{"workflows": []}]}`))
+			}
+		case r.URL.Path == "/api/v1/"+resource+"/42/":
+			body := struct {
+				ID          int32 `json:"Id"`
+				EntityState EntityState
+			}{}
+			b, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(b, &body))
+			_, _ = w.Write(b)
+		case r.URL.Path == "/api/v1/Comments/":
+			comment := struct {
+				Description string
+				General     General
+			}{}
+			b, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(b, &comment))
+			assert.Equal(t, int32(42), comment.General.ID)
+			*comments = append(*comments, comment.Description)
+			_, _ = w.Write([]byte(`{"Id": 1}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(404)
+		}
+	}
+}
+
+func TestUserStoryTransitionTo(t *testing.T) {
+	tests := []struct {
+		name         string
+		state        string
+		opts         []TransitionOption
+		team         *Team
+		wantStateID  int32
+		wantComments []string
+		wantErr      bool
+	}{
+		{
+			name:        "simple transition",
+			state:       "In Progress",
+			wantStateID: 11,
+		},
+		{
+			name:    "comment required",
+			state:   "Done",
+			wantErr: true,
+		},
+		{
+			name:         "with comment",
+			state:        "Done",
+			opts:         []TransitionOption{WithComment("Released in 1.2.0")},
+			wantStateID:  12,
+			wantComments: []string{"Released in 1.2.0"},
+		},
+		{
+			name:        "process level state without a team",
+			state:       "Review",
+			wantStateID: 13,
+		},
+		{
+			name:        "process level state for a team on the process workflow",
+			state:       "Review",
+			team:        &Team{ID: 4},
+			wantStateID: 13,
+		},
+		{
+			name:        "team workflow state",
+			state:       "Review",
+			team:        &Team{ID: 3},
+			wantStateID: 14,
+		},
+		{
+			name:        "process level state not in the team workflow",
+			state:       "In Progress",
+			team:        &Team{ID: 3},
+			wantStateID: 11,
+		},
+		{
+			name:        "workflow override",
+			state:       "Review",
+			opts:        []TransitionOption{InWorkflow(5)},
+			wantStateID: 14,
+		},
+		{
+			name:    "state missing from the workflow override",
+			state:   "In Progress",
+			opts:    []TransitionOption{InWorkflow(5)},
+			wantErr: true,
+		},
+		{
+			name:    "unknown state",
+			state:   "Nope",
+			wantErr: true,
+		},
+		{
+			name:    "already in state",
+			state:   "Open",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comments []string
+			mockClient, teardown := newMockClient(workflowHandler(t, "UserStory", "UserStories", &comments), "example", "abcd1234")
+			defer teardown()
+
+			us := UserStory{
+				client:      mockClient,
+				ID:          42,
+				Project:     &Project{ID: 7},
+				EntityState: &EntityState{ID: 10, Name: "Open"},
+				Team:        tt.team,
+			}
+			updated, err := us.TransitionTo(tt.state, tt.opts...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, comments)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStateID, updated.EntityState.ID)
+			assert.Equal(t, tt.wantComments, comments)
+		})
+	}
+}
+
+func TestBugTransitionTo(t *testing.T) {
+	var comments []string
+	mockClient, teardown := newMockClient(workflowHandler(t, "Bug", "Bugs", &comments), "example", "abcd1234")
+	defer teardown()
+
+	b := Bug{
+		client:          mockClient,
+		ID:              42,
+		Project:         &Project{ID: 7},
+		EntityState:     &EntityState{ID: 10},
+		Team:            &Team{ID: 4},
+		ResponsibleTeam: &TeamAssignment{Team: &Team{ID: 3}},
+	}
+	updated, err := b.TransitionTo("Review", WithComment("Fixed in #123"))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(14), updated.EntityState.ID)
+	assert.Equal(t, []string{"Fixed in #123"}, comments)
+
+	_, err = Bug{ID: 42}.TransitionTo("Review")
+	assert.Error(t, err)
+}
+
+func TestTaskTransitionTo(t *testing.T) {
+	var comments []string
+	mockClient, teardown := newMockClient(workflowHandler(t, "Task", "Tasks", &comments), "example", "abcd1234")
+	defer teardown()
+
+	task := Task{
+		client:      mockClient,
+		ID:          42,
+		Project:     &Project{ID: 7},
+		EntityState: &EntityState{ID: 10},
+		Team:        &Team{ID: 3},
+	}
+	updated, err := task.TransitionTo("In Progress")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(11), updated.EntityState.ID)
+	assert.Empty(t, comments)
+
+	_, err = task.TransitionTo("Done")
+	assert.Error(t, err, "Done requires a comment")
+}
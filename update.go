@@ -0,0 +1,80 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Update is a generic partial update of the entity with the given ID. body should only hold the fields
+// to change, every field left out keeps its current value. The updated entity is decoded into out.
+func (c *Client) Update(out interface{}, entityType string, id int32, body []byte) error {
+	return c.UpdateCtx(c.context(), out, entityType, id, body)
+}
+
+// UpdateCtx is Update with a context that can cancel the request
+func (c *Client) UpdateCtx(ctx context.Context, out interface{}, entityType string, id int32, body []byte) error {
+	if id == 0 {
+		return fmt.Errorf("cannot update %s without an id", entityType)
+	}
+	return c.PostCtx(ctx, out, fmt.Sprintf("%s/%d", entityType, id), nil, body)
+}
+
+// updateBody builds the body of a partial update from entity, holding its id and only the given fields.
+// Fields are named as they are in the JSON (ex. EntityState or Effort) and are included even when they
+// hold a zero value, so they can be used to reset a field.
+func updateBody(entity interface{}, id int32, fields []string) ([]byte, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("no fields given to update")
+	}
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	body := map[string]interface{}{"Id": id}
+	for _, field := range fields {
+		name, value, ok := jsonField(v, field)
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %s", v.Type().Name(), field)
+		}
+		body[name] = value
+	}
+	return json.Marshal(body)
+}
+
+// jsonField finds the exported field of the struct v whose JSON name matches name, ignoring case
+func jsonField(v reflect.Value, name string) (string, interface{}, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		jsonName := f.Name
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" {
+			if tag == "-" {
+				continue
+			}
+			jsonName = tag
+		}
+		if strings.EqualFold(jsonName, name) {
+			return jsonName, v.Field(i).Interface(), true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Fairwinds
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License
+
+package targetprocess
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateBody(t *testing.T) {
+	us := UserStory{
+		ID:          42,
+		Name:        "Do not send me",
+		Effort:      0,
+		EntityState: &EntityState{ID: 5},
+		Team:        &Team{ID: 3},
+	}
+	tests := []struct {
+		name    string
+		fields  []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "single reference",
+			fields: []string{"EntityState"},
+			want:   `{"EntityState":{"Id":5},"Id":42}`,
+		},
+		{
+			name:   "zero value and case insensitive",
+			fields: []string{"effort", "Team"},
+			want:   `{"Effort":0,"Id":42,"Team":{"Id":3}}`,
+		},
+		{
+			name:   "clear a reference",
+			fields: []string{"Feature"},
+			want:   `{"Feature":null,"Id":42}`,
+		},
+		{
+			name:    "unknown field",
+			fields:  []string{"NotAField"},
+			wantErr: true,
+		},
+		{
+			name:    "unexported field",
+			fields:  []string{"client"},
+			wantErr: true,
+		},
+		{
+			name:    "no fields",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := updateBody(us, us.ID, tt.fields)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestUserStoryUpdate(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/UserStories/42/", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"Id": 42, "Effort": 8}`, string(body))
+		_, _ = w.Write([]byte(`{"Id": 42, "Name": "Server side name", "Effort": 8, "EffortToDo": 8}`))
+	})
+	mockClient, teardown := newMockClient(h, "example", "abcd1234")
+	defer teardown()
+
+	us := UserStory{client: mockClient, ID: 42, Name: "Local name", Effort: 8}
+	updated, err := us.Update("Effort")
+	assert.NoError(t, err)
+	assert.Equal(t, "Server side name", updated.Name)
+	assert.Equal(t, float32(8), updated.EffortToDo)
+	assert.Equal(t, mockClient, updated.client)
+
+	_, err = UserStory{ID: 42}.Update("Effort")
+	assert.Error(t, err)
+	_, err = UserStory{client: mockClient}.Update("Effort")
+	assert.Error(t, err)
+}
@@ -14,12 +14,19 @@
 
 package targetprocess
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
 // User matches up with a targetprocess User
 type User struct {
 	CustomFields    []CustomField `json:",omitempty"`
-	CreateDate      DateTime      `json:",omitempty"`
-	ModifyDate      DateTime      `json:",omitempty"`
-	DeleteDate      DateTime      `json:",omitempty"`
+	CreateDate      *DateTime     `json:",omitempty"`
+	ModifyDate      *DateTime     `json:",omitempty"`
+	DeleteDate      *DateTime     `json:",omitempty"`
 	Email           string        `json:",omitempty"`
 	FirstName       string        `json:",omitempty"`
 	GlobalID        string        `json:",omitempty"`
@@ -40,22 +47,35 @@ type UserResponse struct {
 
 // GetUsers will return all users
 func (c *Client) GetUsers(filters ...QueryFilter) ([]User, error) {
-	var ret []User
-	out := UserResponse{}
+	return c.GetUsersCtx(c.context(), filters...)
+}
 
-	err := c.Get(&out, "Users", nil, filters...)
-	if err != nil {
-		return nil, err
-	}
-	ret = append(ret, out.Items...)
-	for out.Next != "" {
-		innerOut := UserResponse{}
-		err := c.GetNext(&innerOut, out.Next)
-		if err != nil {
+// GetUsersCtx is GetUsers with a context that can cancel the requests
+func (c *Client) GetUsersCtx(ctx context.Context, filters ...QueryFilter) ([]User, error) {
+	var ret []User
+	it := c.NewIteratorCtx(ctx, "Users", filters...)
+	for it.Next() {
+		item := User{}
+		if err := it.Decode(&item); err != nil {
 			return ret, err
 		}
-		ret = append(ret, innerOut.Items...)
-		out = innerOut
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetUserByID will return the User with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetUserByID(id int32) (User, error) {
+	return c.GetUserByIDCtx(c.context(), id)
+}
+
+// GetUserByIDCtx is GetUserByID with a context that can cancel the request
+func (c *Client) GetUserByIDCtx(ctx context.Context, id int32) (User, error) {
+	ret := User{}
+	err := c.GetByIDCtx(ctx, &ret, "Users", id)
+	if err != nil {
+		return User{}, errors.Wrap(err, fmt.Sprintf("error getting User with id %d", id))
 	}
 	return ret, nil
 }
@@ -15,6 +15,7 @@
 package targetprocess
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -28,10 +29,10 @@ type UserStory struct {
 	ID                  int32           `json:"Id,omitempty"`
 	Name                string          `json:",omitempty"`
 	Description         string          `json:",omitempty"`
-	StartDate           DateTime        `json:",omitempty"`
-	EndDate             DateTime        `json:",omitempty"`
-	CreateDate          DateTime        `json:",omitempty"`
-	ModifyDate          DateTime        `json:",omitempty"`
+	StartDate           *DateTime       `json:",omitempty"`
+	EndDate             *DateTime       `json:",omitempty"`
+	CreateDate          *DateTime       `json:",omitempty"`
+	ModifyDate          *DateTime       `json:",omitempty"`
 	NumericPriority     float64         `json:",omitempty"`
 	CustomFields        []CustomField   `json:",omitempty"`
 	Effort              float32         `json:",omitempty"`
@@ -41,7 +42,7 @@ type UserStory struct {
 	Progress            float32         `json:",omitempty"`
 	TimeSpent           float32         `json:",omitempty"`
 	TimeRemain          float32         `json:",omitempty"`
-	LastStateChangeDate DateTime        `json:",omitempty"`
+	LastStateChangeDate *DateTime       `json:",omitempty"`
 	InitialEstimate     float32         `json:",omitempty"`
 	Assignments         *Assignments    `json:",omitempty"`
 	ResponsibleTeam     *TeamAssignment `json:",omitempty"`
@@ -50,6 +51,9 @@ type UserStory struct {
 	EntityState         *EntityState    `json:",omitempty"`
 	AssignedUser        *AssignedUser   `json:",omitempty"`
 	Feature             *Feature        `json:",omitempty"`
+	Release             *Release        `json:",omitempty"`
+	Iteration           *Iteration      `json:",omitempty"`
+	TeamIteration       *TeamIteration  `json:",omitempty"`
 }
 
 // UserStoryList is a list of user stories. Can be used to create multiple stories at once
@@ -69,12 +73,17 @@ type UserStoryResponse struct {
 // name, description, and project.
 // If more fields are required, use the Add<Field> method of UserStory to add them
 func NewUserStory(c *Client, name, description, project string) (UserStory, error) {
+	return NewUserStoryCtx(c.context(), c, name, description, project)
+}
+
+// NewUserStoryCtx is NewUserStory with a context that can cancel the requests
+func NewUserStoryCtx(ctx context.Context, c *Client, name, description, project string) (UserStory, error) {
 	us := UserStory{
 		client:      c,
 		Name:        name,
 		Description: description,
 	}
-	err := us.SetProject(project)
+	err := us.SetProjectCtx(ctx, project)
 	if err != nil {
 		return UserStory{}, err
 	}
@@ -84,8 +93,13 @@ func NewUserStory(c *Client, name, description, project string) (UserStory, erro
 
 // SetProject sets the Project field for a user story
 func (us *UserStory) SetProject(project string) error {
+	return us.SetProjectCtx(us.client.context(), project)
+}
+
+// SetProjectCtx is SetProject with a context that can cancel the requests
+func (us *UserStory) SetProjectCtx(ctx context.Context, project string) error {
 	us.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Team: %s", project))
-	p, err := us.client.GetProject(project)
+	p, err := us.client.GetProjectCtx(ctx, project)
 	if err != nil {
 		return err
 	}
@@ -95,8 +109,13 @@ func (us *UserStory) SetProject(project string) error {
 
 // SetTeam sets the Team field for a user story
 func (us *UserStory) SetTeam(team string) error {
+	return us.SetTeamCtx(us.client.context(), team)
+}
+
+// SetTeamCtx is SetTeam with a context that can cancel the requests
+func (us *UserStory) SetTeamCtx(ctx context.Context, team string) error {
 	us.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Team: %s", team))
-	t, err := us.client.GetTeam(team)
+	t, err := us.client.GetTeamCtx(ctx, team)
 	if err != nil {
 		return err
 	}
@@ -106,7 +125,12 @@ func (us *UserStory) SetTeam(team string) error {
 
 // SetFeature sets the Feature field for a user story
 func (us *UserStory) SetFeature(feature string) error {
-	f, err := us.client.GetFeature(feature)
+	return us.SetFeatureCtx(us.client.context(), feature)
+}
+
+// SetFeatureCtx is SetFeature with a context that can cancel the requests
+func (us *UserStory) SetFeatureCtx(ctx context.Context, feature string) error {
+	f, err := us.client.GetFeatureCtx(ctx, feature)
 	if err != nil {
 		return err
 	}
@@ -114,6 +138,54 @@ func (us *UserStory) SetFeature(feature string) error {
 	return nil
 }
 
+// SetRelease sets the Release field for a user story
+func (us *UserStory) SetRelease(release string) error {
+	return us.SetReleaseCtx(us.client.context(), release)
+}
+
+// SetReleaseCtx is SetRelease with a context that can cancel the requests
+func (us *UserStory) SetReleaseCtx(ctx context.Context, release string) error {
+	us.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Release: %s", release))
+	r, err := us.client.GetReleaseCtx(ctx, release)
+	if err != nil {
+		return err
+	}
+	us.Release = &r
+	return nil
+}
+
+// SetIteration sets the Iteration field for a user story
+func (us *UserStory) SetIteration(iteration string) error {
+	return us.SetIterationCtx(us.client.context(), iteration)
+}
+
+// SetIterationCtx is SetIteration with a context that can cancel the requests
+func (us *UserStory) SetIterationCtx(ctx context.Context, iteration string) error {
+	us.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get Iteration: %s", iteration))
+	i, err := us.client.GetIterationCtx(ctx, iteration)
+	if err != nil {
+		return err
+	}
+	us.Iteration = &i
+	return nil
+}
+
+// SetTeamIteration sets the TeamIteration field for a user story
+func (us *UserStory) SetTeamIteration(teamIteration string) error {
+	return us.SetTeamIterationCtx(us.client.context(), teamIteration)
+}
+
+// SetTeamIterationCtx is SetTeamIteration with a context that can cancel the requests
+func (us *UserStory) SetTeamIterationCtx(ctx context.Context, teamIteration string) error {
+	us.client.debugLog(fmt.Sprintf("[targetprocess] Attempting to Get TeamIteration: %s", teamIteration))
+	ti, err := us.client.GetTeamIterationCtx(ctx, teamIteration)
+	if err != nil {
+		return err
+	}
+	us.TeamIteration = &ti
+	return nil
+}
+
 // SetAssignedUserID assigns the UserStory to a User based on their ID number
 func (us *UserStory) SetAssignedUserID(userID int32) {
 	u := User{
@@ -126,6 +198,23 @@ func (us *UserStory) SetAssignedUserID(userID int32) {
 	us.Assignments = &assignments
 }
 
+// GetUserStoryByID will return the UserStory with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetUserStoryByID(id int32) (UserStory, error) {
+	return c.GetUserStoryByIDCtx(c.context(), id)
+}
+
+// GetUserStoryByIDCtx is GetUserStoryByID with a context that can cancel the request
+func (c *Client) GetUserStoryByIDCtx(ctx context.Context, id int32) (UserStory, error) {
+	ret := UserStory{}
+	err := c.GetByIDCtx(ctx, &ret, "UserStories", id)
+	if err != nil {
+		return UserStory{}, errors.Wrap(err, fmt.Sprintf("error getting UserStory with id %d", id))
+	}
+	ret.client = c
+	return ret, nil
+}
+
 // GetUserStories will return all user stories
 //
 // Use with caution if you have a lot and are not setting the MaxPerPage to a high number
@@ -133,24 +222,45 @@ func (us *UserStory) SetAssignedUserID(userID int32) {
 // If you know you have a lot you may want to include the QueryFilter MaxPerPage
 //
 func (c *Client) GetUserStories(page bool, filters ...QueryFilter) ([]UserStory, error) {
+	return c.GetUserStoriesCtx(c.context(), page, filters...)
+}
+
+// GetUserStoriesCtx is GetUserStories with a context that can cancel the requests
+func (c *Client) GetUserStoriesCtx(ctx context.Context, page bool, filters ...QueryFilter) ([]UserStory, error) {
 	var ret []UserStory
-	out := UserStoryResponse{}
+	it := c.NewIteratorCtx(ctx, "UserStories", filters...)
+	if !page {
+		it.MaxPages(1)
+	}
+	for it.Next() {
+		item := UserStory{}
+		if err := it.Decode(&item); err != nil {
+			return ret, err
+		}
+		item.client = c
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetUserStoriesParallel will return all user stories, fetching pages concurrently.
+// See Client.GetAllParallel for how workers and pageSize are used.
+func (c *Client) GetUserStoriesParallel(workers, pageSize int, filters ...QueryFilter) ([]UserStory, error) {
+	return c.GetUserStoriesParallelCtx(c.context(), workers, pageSize, filters...)
+}
 
-	err := c.Get(&out, "UserStories", nil, filters...)
+// GetUserStoriesParallelCtx is GetUserStoriesParallel with a context that can cancel the requests
+func (c *Client) GetUserStoriesParallelCtx(ctx context.Context, workers, pageSize int, filters ...QueryFilter) ([]UserStory, error) {
+	items, err := c.GetAllParallelCtx(ctx, "UserStories", workers, pageSize, filters...)
 	if err != nil {
 		return nil, err
 	}
-	ret = append(ret, out.Items...)
-	if page {
-		for out.Next != "" {
-			innerOut := UserStoryResponse{}
-			err := c.GetNext(&innerOut, out.Next)
-			if err != nil {
-				return ret, err
-			}
-			ret = append(ret, innerOut.Items...)
-			out = innerOut
+	ret := make([]UserStory, len(items))
+	for i, item := range items {
+		if err := json.Unmarshal(item, &ret[i]); err != nil {
+			return nil, errors.Wrap(err, "JSON decode failed on UserStories item")
 		}
+		ret[i].client = c
 	}
 	return ret, nil
 }
@@ -159,6 +269,11 @@ func (c *Client) GetUserStories(page bool, filters ...QueryFilter) ([]UserStory,
 // it returns the ID of the UserStory created as well as a link to the entity
 // on the Target Process frontend
 func (us UserStory) Create() (int32, string, error) {
+	return us.CreateCtx(us.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the requests
+func (us UserStory) CreateCtx(ctx context.Context) (int32, string, error) {
 	client := us.client
 	resp := &struct {
 		ID int32 `json:"Id"`
@@ -169,16 +284,56 @@ func (us UserStory) Create() (int32, string, error) {
 	}
 
 	client.debugLog(fmt.Sprintf("Attempting to POST UserStory: %+v", us))
-	err = client.Post(resp, "UserStory", nil, body)
+	err = client.PostCtx(ctx, resp, "UserStory", nil, body)
 	if err != nil {
 		return 0, "", errors.Wrap(err, fmt.Sprintf("error POSTing UserStory %s", us.Name))
 	}
 	client.debugLog("[targetprocess] Successfully POSTed UserStory")
 	client.debugLog(fmt.Sprintf("[targetprocess] UserStory created. ID: %d", resp.ID))
-	link := GenerateURL(client.account, resp.ID)
+	link := client.EntityURL(resp.ID)
 	return resp.ID, link, nil
 }
 
+// Update sends only the given fields of the UserStory to Targetprocess, leaving every other field untouched,
+// and returns the UserStory as it is after the update.
+// Fields are named as in the JSON, ex. us.Update("EntityState", "Effort")
+func (us UserStory) Update(fields ...string) (UserStory, error) {
+	return us.UpdateCtx(us.client.context(), fields...)
+}
+
+// UpdateCtx is Update with a context that can cancel the request
+func (us UserStory) UpdateCtx(ctx context.Context, fields ...string) (UserStory, error) {
+	client := us.client
+	if client == nil {
+		return UserStory{}, fmt.Errorf("UserStory %d has no client, get it from a Client method", us.ID)
+	}
+	body, err := updateBody(us, us.ID, fields)
+	if err != nil {
+		return UserStory{}, errors.Wrap(err, fmt.Sprintf("error building update for UserStory %d", us.ID))
+	}
+	client.debugLog(fmt.Sprintf("[targetprocess] Attempting to update UserStory %d: %s", us.ID, body))
+	ret := UserStory{}
+	err = client.UpdateCtx(ctx, &ret, "UserStories", us.ID, body)
+	if err != nil {
+		return UserStory{}, errors.Wrap(err, fmt.Sprintf("error updating UserStory %d", us.ID))
+	}
+	ret.client = client
+	return ret, nil
+}
+
+// Delete will delete the UserStory in Targetprocess
+func (us UserStory) Delete() error {
+	return us.DeleteCtx(us.client.context())
+}
+
+// DeleteCtx is Delete with a context that can cancel the request
+func (us UserStory) DeleteCtx(ctx context.Context) error {
+	if us.client == nil {
+		return fmt.Errorf("UserStory %d has no client, get it from a Client method", us.ID)
+	}
+	return us.client.DeleteCtx(ctx, "UserStories", us.ID)
+}
+
 // NewUserStoryList returns a UserStoryList from a list of user stories.
 // Used for batch POSTing of UserStories
 func (c *Client) NewUserStoryList(list []UserStory) *UserStoryList {
@@ -191,6 +346,11 @@ func (c *Client) NewUserStoryList(list []UserStory) *UserStoryList {
 // Create posts a list of user stories to create them
 // returns a list of entity IDs along with a list of links to them
 func (usl UserStoryList) Create() ([]int32, []string, error) {
+	return usl.CreateCtx(usl.client.context())
+}
+
+// CreateCtx is Create with a context that can cancel the requests
+func (usl UserStoryList) CreateCtx(ctx context.Context) ([]int32, []string, error) {
 	client := usl.client
 	resp := &UserStoryResponse{}
 	body, err := json.Marshal(usl.Stories)
@@ -198,7 +358,7 @@ func (usl UserStoryList) Create() ([]int32, []string, error) {
 		return nil, nil, errors.Wrap(err, fmt.Sprintf("error marshaling POST body for UserStoryList %v", usl))
 	}
 	client.debugLog(fmt.Sprintf("[targetprocess] Attempting to POST UserStory: %+v", usl))
-	err = client.Post(resp, "UserStories/bulk", nil, body)
+	err = client.PostCtx(ctx, resp, "UserStories/bulk", nil, body)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, fmt.Sprintf("error POSTing UserStoryList %v", usl))
 	}
@@ -211,7 +371,7 @@ func (usl UserStoryList) Create() ([]int32, []string, error) {
 
 	for _, story := range resp.Items {
 		ret = append(ret, story.ID)
-		links = append(links, GenerateURL(client.account, story.ID))
+		links = append(links, client.EntityURL(story.ID))
 	}
 	client.debugLog(fmt.Sprintf("[targetprocess] User stories created with IDs: %v", ret))
 	return ret, links, nil
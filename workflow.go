@@ -14,6 +14,13 @@
 
 package targetprocess
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
 // Workflow contains metadata for the state of a Workflow.
 type Workflow struct {
 	ID      int32    `json:"Id,omitempty"`
@@ -30,22 +37,35 @@ type WorkflowResponse struct {
 
 // GetWorkflows will return all Workflows
 func (c *Client) GetWorkflows(filters ...QueryFilter) ([]Workflow, error) {
-	var ret []Workflow
-	out := WorkflowResponse{}
+	return c.GetWorkflowsCtx(c.context(), filters...)
+}
 
-	err := c.Get(&out, "Workflow", nil, filters...)
-	if err != nil {
-		return nil, err
-	}
-	ret = append(ret, out.Items...)
-	for out.Next != "" {
-		innerOut := WorkflowResponse{}
-		err := c.GetNext(&innerOut, out.Next)
-		if err != nil {
+// GetWorkflowsCtx is GetWorkflows with a context that can cancel the requests
+func (c *Client) GetWorkflowsCtx(ctx context.Context, filters ...QueryFilter) ([]Workflow, error) {
+	var ret []Workflow
+	it := c.NewIteratorCtx(ctx, "Workflow", filters...)
+	for it.Next() {
+		item := Workflow{}
+		if err := it.Decode(&item); err != nil {
 			return ret, err
 		}
-		ret = append(ret, innerOut.Items...)
-		out = innerOut
+		ret = append(ret, item)
+	}
+	return ret, it.Err()
+}
+
+// GetWorkflowByID will return the Workflow with the given ID. The error returned when it
+// doesn't exist satisfies IsNotFound.
+func (c *Client) GetWorkflowByID(id int32) (Workflow, error) {
+	return c.GetWorkflowByIDCtx(c.context(), id)
+}
+
+// GetWorkflowByIDCtx is GetWorkflowByID with a context that can cancel the request
+func (c *Client) GetWorkflowByIDCtx(ctx context.Context, id int32) (Workflow, error) {
+	ret := Workflow{}
+	err := c.GetByIDCtx(ctx, &ret, "Workflows", id)
+	if err != nil {
+		return Workflow{}, errors.Wrap(err, fmt.Sprintf("error getting Workflow with id %d", id))
 	}
 	return ret, nil
 }